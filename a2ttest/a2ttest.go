@@ -0,0 +1,116 @@
+// Package a2ttest provides helpers for testing code that talks to an a2t
+// server: a MockProvider that records executions and a NewTestServer that
+// serves any provider over httptest.
+package a2ttest
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/traego/a2t"
+)
+
+// Call records a single tool execution.
+type Call struct {
+	GroupID string
+	Tool    string
+	Params  map[string]interface{}
+}
+
+// MockProvider is an in-memory GroupProvider that records every execution.
+// Tools and groups are registered as on GroupProviderImpl.
+type MockProvider struct {
+	*a2t.GroupProviderImpl
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewMockProvider creates a mock provider with groups enabled.
+func NewMockProvider() *MockProvider {
+	return &MockProvider{
+		GroupProviderImpl: a2t.NewGroupProvider(nil),
+	}
+}
+
+// RegisterResult registers a tool that always returns result.
+func (m *MockProvider) RegisterResult(tool *a2t.Tool, result interface{}) {
+	m.RegisterTool(tool, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return result, nil
+	})
+}
+
+// RegisterError registers a tool that always fails with err.
+func (m *MockProvider) RegisterError(tool *a2t.Tool, err error) {
+	m.RegisterTool(tool, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return nil, err
+	})
+}
+
+// ExecuteTool records the call and executes the tool.
+func (m *MockProvider) ExecuteTool(ctx context.Context, toolName string, params map[string]interface{}) (*a2t.ExecuteResponse, error) {
+	m.record(a2t.GroupIDFromContext(ctx), toolName, params)
+	return m.GroupProviderImpl.ExecuteTool(ctx, toolName, params)
+}
+
+// ExecuteGroupTool records the call and executes the tool within the group.
+func (m *MockProvider) ExecuteGroupTool(ctx context.Context, groupID, toolName string, params map[string]interface{}) (*a2t.ExecuteResponse, error) {
+	m.record(groupID, toolName, params)
+	return m.GroupProviderImpl.ExecuteTool(a2t.WithGroupID(ctx, groupID), toolName, params)
+}
+
+// Calls returns every recorded execution in order.
+func (m *MockProvider) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the recorded executions of the named tool in order.
+func (m *MockProvider) CallsTo(toolName string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, call := range m.calls {
+		if call.Tool == toolName {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Called reports whether the named tool was executed at least once.
+func (m *MockProvider) Called(toolName string) bool {
+	return len(m.CallsTo(toolName)) > 0
+}
+
+// Reset clears the recorded executions.
+func (m *MockProvider) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+}
+
+// record stores a copy of the params so later changes by hooks or the
+// executor don't alter the recorded call.
+func (m *MockProvider) record(groupID, toolName string, params map[string]interface{}) {
+	copied := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		copied[k] = v
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{GroupID: groupID, Tool: toolName, Params: copied})
+}
+
+// NewTestServer starts an httptest.Server serving the provider. The caller
+// must Close it.
+func NewTestServer(provider a2t.ToolProvider, opts ...a2t.ServerOption) *httptest.Server {
+	return httptest.NewServer(a2t.NewServer(provider, opts...).Handler())
+}
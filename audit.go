@@ -0,0 +1,180 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditEntry records a single tool execution.
+type AuditEntry struct {
+	Time      time.Time              `json:"time"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Caller    string                 `json:"caller,omitempty"`
+	GroupID   string                 `json:"group_id,omitempty"`
+	Tool      string                 `json:"tool"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	Status    string                 `json:"status"` // "ok" or "error"
+	ErrorCode string                 `json:"error_code,omitempty"`
+	Duration  time.Duration          `json:"duration_ns"`
+}
+
+// AuditSink receives an entry after every tool execution.
+type AuditSink interface {
+	Record(ctx context.Context, entry AuditEntry)
+}
+
+// WithAuditSink records every tool execution to the sink. Parameters are
+// redacted using the server's sensitive keys.
+func WithAuditSink(sink AuditSink) ServerOption {
+	return func(s *Server) {
+		s.audit = sink
+	}
+}
+
+// DefaultSensitiveKeys are the parameter names redacted from audit entries
+// and replay records unless overridden with WithSensitiveKeys.
+var DefaultSensitiveKeys = []string{"password", "secret", "token", "api_key", "authorization"}
+
+// redactedValue replaces the values of sensitive parameters.
+const redactedValue = "[REDACTED]"
+
+// WithSensitiveKeys replaces the parameter names redacted from audit entries
+// and replay records. A key matches any parameter whose name contains it,
+// ignoring case.
+func WithSensitiveKeys(keys ...string) ServerOption {
+	return func(s *Server) {
+		s.sensitiveKeys = keys
+	}
+}
+
+// redact returns a copy of params with sensitive values replaced, including
+// in nested objects and arrays.
+func (s *Server) redact(params map[string]interface{}) map[string]interface{} {
+	if params == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if s.isSensitive(k) {
+			out[k] = redactedValue
+			continue
+		}
+		out[k] = s.redactValue(v)
+	}
+	return out
+}
+
+func (s *Server) redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return s.redact(val)
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = s.redactValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// isSensitive reports whether a parameter name matches a sensitive key.
+func (s *Server) isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, key := range s.sensitiveKeys {
+		if strings.Contains(name, strings.ToLower(key)) {
+			return true
+		}
+	}
+	return false
+}
+
+// audited runs an execution and records it to the audit sink, if any.
+func (s *Server) audited(ctx context.Context, groupID, toolName string, params map[string]interface{}, run func() (*ExecuteResponse, error)) (*ExecuteResponse, error) {
+	if s.audit == nil {
+		return run()
+	}
+
+	entry := AuditEntry{
+		Time:      time.Now(),
+		RequestID: RequestIDFromContext(ctx),
+		GroupID:   groupID,
+		Tool:      toolName,
+		Params:    s.redact(params),
+		Status:    "ok",
+	}
+	if claims := ClaimsFromContext(ctx); claims != nil {
+		entry.Caller = claims.Subject
+	} else if info, ok := RequestInfoFromContext(ctx); ok {
+		entry.Caller = info.RemoteAddr
+	}
+
+	resp, err := run()
+
+	entry.Duration = time.Since(entry.Time)
+	var detail *ErrorDetail
+	switch {
+	case err != nil && errors.As(err, &detail):
+		entry.Status, entry.ErrorCode = "error", detail.Code
+	case err != nil:
+		entry.Status, entry.ErrorCode = "error", "internal_error"
+	case resp.Error != nil:
+		entry.Status, entry.ErrorCode = "error", resp.Error.Code
+	}
+
+	s.audit.Record(ctx, entry)
+	return resp, err
+}
+
+// JSONLinesAuditSink writes audit entries as newline-delimited JSON.
+type JSONLinesAuditSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewJSONLinesAuditSink creates a sink writing to w.
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{w: w}
+}
+
+// OpenAuditLog opens (or creates) a JSON-lines audit file for appending.
+func OpenAuditLog(path string) (*JSONLinesAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLinesAuditSink{w: f, closer: f}, nil
+}
+
+// Record writes the entry as one JSON line. Write failures are logged.
+func (a *JSONLinesAuditSink) Record(ctx context.Context, entry AuditEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		slog.ErrorContext(ctx, "encoding audit entry", "tool", entry.Tool, "error", err)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, err := a.w.Write(append(line, '\n')); err != nil {
+		slog.ErrorContext(ctx, "writing audit entry", "tool", entry.Tool, "error", err)
+	}
+}
+
+// Close closes the underlying file when the sink was opened with OpenAuditLog.
+func (a *JSONLinesAuditSink) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
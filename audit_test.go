@@ -0,0 +1,28 @@
+package a2t
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAuditRedactsSensitiveParams(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	p.RegisterTool(NewTool("login", "Log in"), echoExecutor)
+	sink := &recordingSink{}
+	h := NewServer(p, WithAuditSink(sink)).Handler()
+
+	serve(h, "POST", "/tools/login", `{"user": "ada", "Password": "hunter2", "profile": {"api_key": "k"}, "tokens": ["a"]}`)
+
+	if len(sink.entries) != 1 {
+		t.Fatalf("got %d audit entries, want 1", len(sink.entries))
+	}
+	want := map[string]interface{}{
+		"user":     "ada",
+		"Password": redactedValue,
+		"profile":  map[string]interface{}{"api_key": redactedValue},
+		"tokens":   redactedValue,
+	}
+	if got := sink.entries[0].Params; !reflect.DeepEqual(got, want) {
+		t.Errorf("audited params = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package a2t
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/swaggest/usecase/status"
+)
+
+// Claims describes the authenticated caller of a request.
+type Claims struct {
+	Subject string
+	Scopes  []string
+	Extra   map[string]interface{}
+}
+
+// HasScope reports whether the caller was granted the scope.
+func (c *Claims) HasScope(scope string) bool {
+	return c != nil && containsString(c.Scopes, scope)
+}
+
+// AuthValidator authenticates a request and returns the caller's claims.
+// Returning nil claims and a nil error lets the request through anonymously;
+// an error rejects it with 401.
+type AuthValidator func(r *http.Request) (*Claims, error)
+
+// WithAuth authenticates every request with the validator. The resulting
+// claims are available through ClaimsFromContext and are checked against
+// the scopes tools require.
+func WithAuth(validator AuthValidator) ServerOption {
+	return func(s *Server) {
+		s.auth = validator
+	}
+}
+
+// authenticate runs the auth validator and stores the claims in the
+// request context.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := s.auth(r)
+		if err != nil {
+			writeError(w, r, http.StatusUnauthorized, &ErrorDetail{
+				Code:    "unauthorized",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+	})
+}
+
+// authorize checks that the caller holds every scope the tool requires.
+// Tools the provider can't look up are left for the provider to report.
+func (s *Server) authorize(ctx context.Context, toolName string) error {
+	getter, ok := s.provider.(ToolGetter)
+	if !ok {
+		return nil
+	}
+	tool, err := getter.GetTool(ctx, toolName)
+	if err != nil {
+		return nil
+	}
+
+	if missing := missingScopes(ClaimsFromContext(ctx), tool.Scopes); len(missing) > 0 {
+		return status.Wrap(&ErrorDetail{
+			Code:    "insufficient_scope",
+			Message: "Tool " + toolName + " requires scope: " + strings.Join(missing, ", "),
+		}, status.PermissionDenied)
+	}
+	return nil
+}
+
+// missingScopes returns the required scopes the caller was not granted.
+func missingScopes(claims *Claims, required []string) []string {
+	var missing []string
+	for _, scope := range required {
+		if !claims.HasScope(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
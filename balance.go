@@ -0,0 +1,82 @@
+package a2t
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// WeightedExecutor is one of several interchangeable executors of a
+// balanced tool.
+type WeightedExecutor struct {
+	Executor ToolExecutor
+
+	// Weight is the executor's share of calls relative to the others. Zero
+	// or less counts as 1.
+	Weight int
+
+	// Healthy reports whether the executor can take calls, such as whether
+	// its circuit breaker is closed. Nil means always.
+	Healthy func() bool
+}
+
+// RegisterToolBalanced registers a tool backed by several interchangeable
+// executors, such as workers for the same backend. Each call goes to one
+// executor, picked by smooth weighted round-robin among the healthy ones,
+// so an executor of weight 2 gets twice the calls of one of weight 1,
+// interleaved. When none is healthy, calls fail with tool_unavailable. It
+// panics when executors is empty.
+func (p *SimpleProvider) RegisterToolBalanced(tool *Tool, executors []WeightedExecutor) {
+	if len(executors) == 0 {
+		panic(fmt.Sprintf("a2t: tool %s: no executors to balance", qualifiedName(tool)))
+	}
+	b := &balancer{
+		executors: append([]WeightedExecutor(nil), executors...),
+		current:   make([]int, len(executors)),
+	}
+	p.RegisterTool(tool, b.execute)
+}
+
+// balancer picks executors by smooth weighted round-robin.
+type balancer struct {
+	mu        sync.Mutex
+	executors []WeightedExecutor
+	current   []int
+}
+
+func (b *balancer) execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	executor := b.next()
+	if executor == nil {
+		return nil, &ErrorDetail{
+			Code:    "tool_unavailable",
+			Message: "No executor is available for tool: " + ToolNameFromContext(ctx),
+		}
+	}
+	return executor(ctx, params)
+}
+
+// next returns the executor for the next call, or nil when none is healthy.
+// Each healthy executor gains its weight, and the one furthest ahead is
+// picked and set back by the total.
+func (b *balancer) next() ToolExecutor {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	best, total := -1, 0
+	for i, e := range b.executors {
+		if e.Healthy != nil && !e.Healthy() {
+			continue
+		}
+		weight := max(e.Weight, 1)
+		b.current[i] += weight
+		total += weight
+		if best < 0 || b.current[i] > b.current[best] {
+			best = i
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	b.current[best] -= total
+	return b.executors[best].Executor
+}
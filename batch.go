@@ -0,0 +1,205 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+// BatchCall is one execution of a batch.
+type BatchCall struct {
+	Name    string                 `json:"name" required:"true" description:"Tool name"`
+	GroupID string                 `json:"group_id,omitempty" description:"Group to execute the tool in"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	IfMatch string                 `json:"if_match,omitempty" description:"Tool ETag the call expects, as in an If-Match header"`
+}
+
+// BatchExecuteInput represents input for executing several tools at once.
+type BatchExecuteInput struct {
+	Calls []BatchCall `json:"calls" required:"true" minItems:"1"`
+
+	request *http.Request
+}
+
+// LoadFromHTTPRequest decodes the batch from the JSON body.
+func (in *BatchExecuteInput) LoadFromHTTPRequest(r *http.Request) error {
+	in.request = r
+	if err := json.NewDecoder(r.Body).Decode(in); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return &ErrorDetail{
+				Code:    "payload_too_large",
+				Message: fmt.Sprintf("Batch exceeds the maximum size of %d bytes", tooLarge.Limit),
+				Status:  http.StatusRequestEntityTooLarge,
+			}
+		}
+		return &ErrorDetail{
+			Code:    "invalid_json",
+			Message: "Invalid JSON body: " + err.Error(),
+		}
+	}
+	return nil
+}
+
+// BatchResult is the outcome of one call of a batch, in the execute
+// response format, with an HTTP status for the call.
+type BatchResult struct {
+	Status int `json:"status"`
+	ExecuteResponse
+}
+
+// BatchResponse holds the results of a batch, in call order. It is answered
+// with 200 when every call succeeded and 207 Multi-Status otherwise.
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+
+	status int
+}
+
+// HTTPStatus implements rest.OutputWithHTTPStatus.
+func (b *BatchResponse) HTTPStatus() int {
+	return b.status
+}
+
+// ExpectedHTTPStatuses implements rest.OutputWithHTTPStatus.
+func (b *BatchResponse) ExpectedHTTPStatuses() []int {
+	return []int{http.StatusOK, http.StatusMultiStatus}
+}
+
+// batchExecuteUsecase runs the calls of a batch concurrently.
+func (s *Server) batchExecuteUsecase() usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, in BatchExecuteInput, output *BatchResponse) error {
+		if err := validateBatch(in.Calls, s.effectiveLimits(ctx).MaxBatchCalls); err != nil {
+			return status.Wrap(err, status.InvalidArgument)
+		}
+
+		output.Results = s.runBatch(ctx, in.request, in.Calls)
+		output.status = http.StatusOK
+		for _, result := range output.Results {
+			if result.Error != nil {
+				output.status = http.StatusMultiStatus
+				break
+			}
+		}
+		return nil
+	})
+
+	u.SetName("batchExecuteTools")
+	u.SetTags("Tools")
+	u.SetTitle("Execute Tools in Batch")
+	u.SetDescription("Executes several tools concurrently, each subject to the usual checks and limits. " +
+		"Every result carries an HTTP status of its own. " +
+		"The batch is answered with 200 when all calls succeed and 207 otherwise.")
+	u.SetExpectedErrors(status.InvalidArgument)
+
+	return u
+}
+
+// validateBatch rejects a batch with no calls, more than maxCalls calls or a
+// call without a name.
+func validateBatch(calls []BatchCall, maxCalls int) *ErrorDetail {
+	if len(calls) == 0 {
+		return &ErrorDetail{Code: "invalid_params", Message: "calls must not be empty"}
+	}
+	if err := checkBatchSize(len(calls), maxCalls); err != nil {
+		return err
+	}
+	for i, call := range calls {
+		if call.Name == "" {
+			return &ErrorDetail{Code: "invalid_params", Message: fmt.Sprintf("calls[%d].name is required", i)}
+		}
+	}
+	return nil
+}
+
+// checkBatchSize rejects a batch of more than maxCalls calls.
+func checkBatchSize(calls, maxCalls int) *ErrorDetail {
+	if calls > maxCalls {
+		return &ErrorDetail{
+			Code:    "invalid_params",
+			Message: fmt.Sprintf("a batch holds at most %d calls, got %d", maxCalls, calls),
+		}
+	}
+	return nil
+}
+
+// limitBody caps the request body at the server's maximum upload size.
+func (s *Server) limitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxUploadSize)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// batchConcurrency is how many calls of a batch run at once. Each call is
+// still subject to the execution limits.
+const batchConcurrency = 8
+
+// runBatch runs the calls of a batch, at most batchConcurrency at a time,
+// and returns their results in call order.
+func (s *Server) runBatch(ctx context.Context, r *http.Request, calls []BatchCall) []BatchResult {
+	results := make([]BatchResult, len(calls))
+	runBounded(len(calls), func(i int) {
+		results[i] = s.batchCall(ctx, r, calls[i])
+	})
+	return results
+}
+
+// runBounded calls fn for every index below n, at most batchConcurrency at a
+// time, and returns once all calls have.
+func runBounded(n int, fn func(i int)) {
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(batchConcurrency, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
+
+// batchCall runs one call through the execute usecases.
+func (s *Server) batchCall(ctx context.Context, r *http.Request, call BatchCall) BatchResult {
+	output := &ExecuteResponse{}
+	var err error
+	switch {
+	case call.GroupID != "":
+		if !s.provider.GetCapabilities().Features.Groups {
+			err = groupsNotServed()
+			break
+		}
+		err = s.executeGroupToolUsecase().Interact(ctx, ExecuteGroupToolInput{
+			ID: call.GroupID, Name: call.Name, Params: call.Params, IfMatch: call.IfMatch, request: r,
+		}, output)
+	default:
+		err = s.executeToolUsecase().Interact(ctx, ExecuteToolInput{
+			Name: call.Name, Params: call.Params, IfMatch: call.IfMatch, request: r,
+		}, output)
+	}
+
+	if err != nil {
+		code, _ := makeErrResp(ctx, err)
+		return BatchResult{Status: code, ExecuteResponse: ExecuteResponse{Error: withRequestID(ctx, outcomeError(err))}}
+	}
+	result := BatchResult{Status: http.StatusOK, ExecuteResponse: *output}
+	if output.Error != nil {
+		// Errors a single call gets in a 200 envelope are failures here
+		result.Status = errorStatus(output.Error.Code)
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchAndRawShareErrorStatuses(t *testing.T) {
+	p := NewSimpleProvider(nil)
+	p.RegisterTool(NewTool("echo", "Echo params"), echoExecutor)
+	p.RegisterTool(NewTool("offline", "Switched off").WithAvailable(func(context.Context) bool { return false }), echoExecutor)
+	h := NewServer(p).Handler()
+
+	rec := serve(h, "POST", "/tools/batch", `{"calls": [{"name": "echo"}, {"name": "offline"}, {"name": "missing"}]}`)
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("batch status %d, want 207", rec.Code)
+	}
+	var batch BatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &batch); err != nil {
+		t.Fatal(err)
+	}
+	want := []int{http.StatusOK, http.StatusServiceUnavailable, http.StatusNotFound}
+	for i, result := range batch.Results {
+		if result.Status != want[i] {
+			t.Errorf("call %d: status %d, want %d", i, result.Status, want[i])
+		}
+	}
+
+	for _, name := range []string{"offline", "missing"} {
+		raw := serve(h, "POST", "/tools/"+name+"?raw=true", "{}")
+		call := serve(h, "POST", "/tools/batch", fmt.Sprintf(`{"calls": [{"name": %q}]}`, name))
+		if err := json.Unmarshal(call.Body.Bytes(), &batch); err != nil {
+			t.Fatal(err)
+		}
+		if raw.Code != batch.Results[0].Status {
+			t.Errorf("%s: raw status %d, batch status %d", name, raw.Code, batch.Results[0].Status)
+		}
+	}
+}
+
+func TestBatchBoundsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	p := NewSimpleProvider(nil)
+	p.RegisterTool(NewTool("slow", "Sleep briefly"), func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil, nil
+	})
+	h := NewServer(p).Handler()
+
+	calls := strings.Repeat(`{"name": "slow"},`, 3*batchConcurrency)
+	rec := serve(h, "POST", "/tools/batch", `{"calls": [`+strings.TrimSuffix(calls, ",")+`]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("batch status %d: %s", rec.Code, rec.Body)
+	}
+	if peak > batchConcurrency {
+		t.Errorf("%d calls ran at once, want at most %d", peak, batchConcurrency)
+	}
+}
+
+func TestBatchCapsCalls(t *testing.T) {
+	caps := NewCapabilities().WithRPC("")
+	caps.Limits = &LimitsConfig{MaxBatchCalls: 2}
+	p := NewSimpleProvider(caps)
+	p.RegisterTool(NewTool("echo", "Echo params"), echoExecutor)
+	h := NewServer(p, WithMaxUploadSize(1024)).Handler()
+
+	rec := serve(h, "POST", "/tools/batch", `{"calls": [{"name": "echo"}, {"name": "echo"}]}`)
+	if rec.Code != http.StatusOK {
+		t.Errorf("batch at the cap: status %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = serve(h, "POST", "/tools/batch", `{"calls": [{"name": "echo"}, {"name": "echo"}, {"name": "echo"}]}`)
+	var resp ErrorResponse
+	if rec.Code != http.StatusBadRequest || json.Unmarshal(rec.Body.Bytes(), &resp) != nil || resp.Error.Code != "invalid_params" {
+		t.Errorf("batch over the cap: status %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = serve(h, "POST", "/tools/batch", `{"calls": [{"name": "echo", "params": {"pad": "`+strings.Repeat("x", 2048)+`"}}]}`)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversize body: status %d: %s", rec.Code, rec.Body)
+	}
+
+	msg := `{"jsonrpc": "2.0", "id": 1, "method": "executeTool", "params": {"name": "echo"}}`
+	var rpcResp RPCResponse
+	rec = serve(h, "POST", "/rpc", "["+strings.Repeat(msg+",", 2)+msg+"]")
+	if err := json.Unmarshal(rec.Body.Bytes(), &rpcResp); err != nil || rpcResp.Error == nil || rpcResp.Error.Code != RPCInvalidParams {
+		t.Errorf("RPC batch over the cap: %s", rec.Body)
+	}
+
+	var doc Capabilities
+	_ = json.Unmarshal(serve(h, "GET", DefaultWellKnownPath, "").Body.Bytes(), &doc)
+	if doc.Limits == nil || doc.Limits.MaxBatchCalls != 2 {
+		t.Errorf("capabilities limits = %+v, want max_batch_calls 2", doc.Limits)
+	}
+}
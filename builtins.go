@@ -0,0 +1,44 @@
+package a2t
+
+import (
+	"context"
+	"time"
+)
+
+// BuiltinPrefix marks the reserved namespace of built-in tools. The tools
+// RegisterBuiltins adds are left out of listings unless builtins are
+// included; other tools named with the prefix are listed as usual.
+const BuiltinPrefix = "_"
+
+// RegisterBuiltins registers the _ping and _echo diagnostic tools, for
+// connectivity checks from agents. _ping returns {"pong": true} with the
+// protocol version and server time; _echo returns its params.
+func RegisterBuiltins(p *SimpleProvider) {
+	ping := NewTool("_ping", "Check connectivity; returns pong with the server version and time").WithReadOnly()
+	p.RegisterTool(ping, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return map[string]interface{}{
+			"pong":    true,
+			"version": p.capabilities.Version,
+			"time":    time.Now().UTC().Format(time.RFC3339Nano),
+		}, nil
+	})
+
+	echo := NewTool("_echo", "Return the params as sent").WithReadOnly()
+	p.RegisterTool(echo, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return params, nil
+	})
+
+	p.catalogMu.Lock()
+	defer p.catalogMu.Unlock()
+	if p.builtins == nil {
+		p.builtins = make(map[string]bool)
+	}
+	p.builtins[ping.Name] = true
+	p.builtins[echo.Name] = true
+}
+
+// isBuiltin reports whether a tool was added by RegisterBuiltins. The
+// caller must hold p.catalogMu.
+func (p *SimpleProvider) isBuiltin(tool *Tool) bool {
+	return tool.GroupID == "" && p.builtins[tool.Name]
+}
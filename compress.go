@@ -0,0 +1,139 @@
+package a2t
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultCompressionMinSize is the smallest response body compressed when
+// WithCompression is given a non-positive threshold.
+const DefaultCompressionMinSize = 1024
+
+// WithCompression compresses JSON responses to GET requests, such as tool
+// and group listings and the capabilities document, with gzip or deflate
+// according to the client's Accept-Encoding. Bodies smaller than minSize
+// bytes are sent uncompressed. HEAD requests get the headers the matching
+// GET would.
+func WithCompression(minSize int) ServerOption {
+	return func(s *Server) {
+		if minSize <= 0 {
+			minSize = DefaultCompressionMinSize
+		}
+		s.compressionMinSize = minSize
+	}
+}
+
+// compress buffers GET responses and compresses them when the client
+// accepts a supported encoding and the body reaches the size threshold.
+// HEAD requests are served as GET to learn the body, whose encoding and
+// length are then sent without it.
+func (s *Server) compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		head := r.Method == http.MethodHead
+		if (r.Method != http.MethodGet && !head) || s.isExport(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buf := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		if head {
+			get := r.Clone(r.Context())
+			get.Method = http.MethodGet
+			next.ServeHTTP(buf, get)
+		} else {
+			next.ServeHTTP(buf, r)
+		}
+
+		h := w.Header()
+		if buf.body.Len() < s.compressionMinSize || h.Get("Content-Encoding") != "" ||
+			!strings.HasPrefix(h.Get("Content-Type"), "application/json") {
+			if head {
+				h.Set("Content-Length", strconv.Itoa(buf.body.Len()))
+			}
+			w.WriteHeader(buf.status)
+			if !head {
+				_, _ = w.Write(buf.body.Bytes())
+			}
+			return
+		}
+
+		var compressed bytes.Buffer
+		var zw io.WriteCloser
+		if encoding == "gzip" {
+			zw = gzip.NewWriter(&compressed)
+		} else {
+			zw = zlib.NewWriter(&compressed)
+		}
+		_, _ = zw.Write(buf.body.Bytes())
+		_ = zw.Close()
+
+		h.Set("Content-Encoding", encoding)
+		h.Set("Content-Length", strconv.Itoa(compressed.Len()))
+		w.WriteHeader(buf.status)
+		if !head {
+			_, _ = w.Write(compressed.Bytes())
+		}
+	})
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header,
+// preferring gzip and honoring q=0 exclusions. It returns "" when neither
+// is acceptable.
+func negotiateEncoding(accept string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		ok := true
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				ok = err == nil && q > 0
+			}
+		}
+		accepted[name] = ok
+	}
+
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if ok, listed := accepted[encoding]; listed {
+			if ok {
+				return encoding
+			}
+			continue
+		}
+		if accepted["*"] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// bufferedResponse holds the status and body written by a handler so they
+// can be inspected before being sent.
+type bufferedResponse struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
@@ -0,0 +1,32 @@
+package a2t
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCompressionHeadMatchesGet(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	for i := 0; i < 20; i++ {
+		p.RegisterTool(NewTool(fmt.Sprintf("tool%d", i), "A tool with a description long enough to compress"), echoExecutor)
+	}
+	h := NewServer(p, WithCompression(64)).Handler()
+
+	get := serve(h, "GET", "/tools", "", "Accept-Encoding", "gzip")
+	head := serve(h, "HEAD", "/tools", "", "Accept-Encoding", "gzip")
+	if get.Code != http.StatusOK || head.Code != http.StatusOK {
+		t.Fatalf("status GET %d, HEAD %d", get.Code, head.Code)
+	}
+	for _, name := range []string{"Content-Encoding", "Content-Length", "Content-Type", "Vary"} {
+		if got, want := head.Header().Get(name), get.Header().Get(name); got != want {
+			t.Errorf("HEAD %s = %q, GET has %q", name, got, want)
+		}
+	}
+	if get.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("GET was not compressed")
+	}
+	if head.Body.Len() != 0 {
+		t.Errorf("HEAD sent a %d byte body", head.Body.Len())
+	}
+}
@@ -0,0 +1,77 @@
+package a2t
+
+import (
+	"encoding/base64"
+)
+
+// ContentBlock is one typed piece of tool output, following the MCP content
+// model. Executors return a []ContentBlock (or a single ContentBlock) to
+// respond with content instead of a plain result.
+type ContentBlock struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	URI      string `json:"uri,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// Content block types.
+const (
+	ContentText         = "text"
+	ContentImage        = "image"
+	ContentResourceLink = "resource_link"
+)
+
+// NewTextContent creates a text content block.
+func NewTextContent(text string) ContentBlock {
+	return ContentBlock{
+		Type: ContentText,
+		Text: text,
+	}
+}
+
+// NewImageContent creates an image content block with base64-encoded data.
+func NewImageContent(data []byte, mimeType string) ContentBlock {
+	return ContentBlock{
+		Type:     ContentImage,
+		Data:     base64.StdEncoding.EncodeToString(data),
+		MimeType: mimeType,
+	}
+}
+
+// NewResourceLinkContent creates a content block linking to a resource.
+func NewResourceLinkContent(uri, name, mimeType string) ContentBlock {
+	return ContentBlock{
+		Type:     ContentResourceLink,
+		URI:      uri,
+		Name:     name,
+		MimeType: mimeType,
+	}
+}
+
+// NewExecuteContent creates an execute response carrying content blocks.
+func NewExecuteContent(blocks ...ContentBlock) *ExecuteResponse {
+	return &ExecuteResponse{
+		Content: blocks,
+	}
+}
+
+// contentResponse builds a response from an executor result, placing content
+// blocks in Content and any other value in Result. An *ExecuteResponse, such
+// as one built with NewExecuteResponse, is used as is.
+func contentResponse(result interface{}) *ExecuteResponse {
+	switch v := result.(type) {
+	case *ExecuteResponse:
+		if v == nil {
+			return &ExecuteResponse{}
+		}
+		return v
+	case []ContentBlock:
+		return &ExecuteResponse{Content: v}
+	case ContentBlock:
+		return &ExecuteResponse{Content: []ContentBlock{v}}
+	default:
+		return &ExecuteResponse{Result: result}
+	}
+}
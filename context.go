@@ -0,0 +1,178 @@
+package a2t
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// RequestInfo describes the HTTP request that triggered a tool execution.
+type RequestInfo struct {
+	Method     string
+	RemoteAddr string
+	Headers    http.Header
+}
+
+// sensitiveHeaders are withheld from RequestInfo unless explicitly exposed
+// with WithExposedHeaders.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-Api-Key",
+}
+
+type requestInfoKey struct{}
+
+type groupIDKey struct{}
+
+type listOptionsKey struct{}
+
+type requestIDKey struct{}
+
+type claimsKey struct{}
+
+type filesKey struct{}
+
+type languagesKey struct{}
+
+type toolNameKey struct{}
+
+// ListOptions carries optional listing parameters that go beyond the
+// positional arguments of ListTools and ListGroups.
+type ListOptions struct {
+	// Sort is a sort key, optionally prefixed with "-" for descending order.
+	Sort string
+
+	// SearchFields selects what a tool search query matches: "name",
+	// "description" and "params" (parameter names and descriptions).
+	// Empty means name and description.
+	SearchFields []string
+
+	// Depth selects how many levels of descendants a group listing with a
+	// parent ID includes: 0 for only the parent, -1 for the whole subtree.
+	// Nil means direct children.
+	Depth *int
+
+	// ReadOnly limits a tool listing to read-only tools.
+	ReadOnly bool
+
+	// IncludeBuiltins adds the tools RegisterBuiltins registered to a tool
+	// listing.
+	IncludeBuiltins bool
+
+	// Highlight reports where each tool matched the search query.
+	Highlight bool
+}
+
+// WithListOptions returns a copy of ctx carrying list options.
+func WithListOptions(ctx context.Context, opts ListOptions) context.Context {
+	return context.WithValue(ctx, listOptionsKey{}, opts)
+}
+
+// ListOptionsFromContext returns the list options stored in ctx, or the
+// zero value when none are set.
+func ListOptionsFromContext(ctx context.Context) ListOptions {
+	opts, _ := ctx.Value(listOptionsKey{}).(ListOptions)
+	return opts
+}
+
+// WithRequestInfo returns a copy of ctx carrying the request info.
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request info stored in ctx, if any.
+func RequestInfoFromContext(ctx context.Context) (*RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info, ok
+}
+
+// WithRequestID returns a copy of ctx carrying the request's correlation ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the correlation ID of the request being
+// served, taken from the X-Request-ID header or generated by the server.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithClaims returns a copy of ctx carrying the authenticated caller's claims.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the caller's claims, or nil for anonymous callers.
+func ClaimsFromContext(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*Claims)
+	return claims
+}
+
+// WithFiles returns a copy of ctx carrying uploaded files keyed by form field.
+func WithFiles(ctx context.Context, files map[string]io.Reader) context.Context {
+	return context.WithValue(ctx, filesKey{}, files)
+}
+
+// FilesFromContext returns the files uploaded with a multipart/form-data
+// request, keyed by form field. Readers are only valid while the executor runs.
+func FilesFromContext(ctx context.Context) map[string]io.Reader {
+	files, _ := ctx.Value(filesKey{}).(map[string]io.Reader)
+	return files
+}
+
+// WithLanguages returns a copy of ctx carrying the caller's preferred
+// languages, most preferred first, as lowercase BCP 47 tags.
+func WithLanguages(ctx context.Context, langs []string) context.Context {
+	return context.WithValue(ctx, languagesKey{}, langs)
+}
+
+// LanguagesFromContext returns the caller's preferred languages, taken from
+// the Accept-Language header. It is empty when none were given.
+func LanguagesFromContext(ctx context.Context) []string {
+	langs, _ := ctx.Value(languagesKey{}).([]string)
+	return langs
+}
+
+// WithGroupID returns a copy of ctx recording the group a tool is executed in.
+func WithGroupID(ctx context.Context, groupID string) context.Context {
+	return context.WithValue(ctx, groupIDKey{}, groupID)
+}
+
+// GroupIDFromContext returns the group a tool is being executed in. It is
+// empty for calls made through the flat tools endpoint.
+func GroupIDFromContext(ctx context.Context) string {
+	groupID, _ := ctx.Value(groupIDKey{}).(string)
+	return groupID
+}
+
+// WithToolName returns a copy of ctx recording the tool being executed.
+func WithToolName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, toolNameKey{}, name)
+}
+
+// ToolNameFromContext returns the name of the tool being executed, for
+// executor middleware shared by many tools.
+func ToolNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(toolNameKey{}).(string)
+	return name
+}
+
+// newRequestInfo captures request metadata, dropping sensitive headers that
+// are not in the exposed set.
+func newRequestInfo(r *http.Request, exposed map[string]bool) *RequestInfo {
+	headers := r.Header.Clone()
+	for _, name := range sensitiveHeaders {
+		if !exposed[name] {
+			headers.Del(name)
+		}
+	}
+
+	return &RequestInfo{
+		Method:     r.Method,
+		RemoteAddr: r.RemoteAddr,
+		Headers:    headers,
+	}
+}
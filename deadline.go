@@ -0,0 +1,32 @@
+package a2t
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/swaggest/usecase/status"
+)
+
+// DeadlineHeader carries an RFC 3339 timestamp after which the client no
+// longer needs the result. It bounds the context passed to executors.
+const DeadlineHeader = "X-A2T-Deadline"
+
+// withDeadline applies the request's DeadlineHeader, if any, to ctx.
+func withDeadline(ctx context.Context, r *http.Request) (context.Context, context.CancelFunc, error) {
+	value := r.Header.Get(DeadlineHeader)
+	if value == "" {
+		return ctx, func() {}, nil
+	}
+
+	deadline, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return ctx, func() {}, status.Wrap(&ErrorDetail{
+			Code:    "invalid_deadline",
+			Message: DeadlineHeader + " must be an RFC 3339 timestamp: " + value,
+		}, status.InvalidArgument)
+	}
+
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	return ctx, cancel, nil
+}
@@ -0,0 +1,108 @@
+package a2t
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDescriptionTTL is how long resolved descriptions are cached when
+// SetDescriptionResolver is given no TTL.
+const DefaultDescriptionTTL = time.Minute
+
+// descriptionLanguages is how many of the caller's preferred languages the
+// resolver is given and descriptions are cached by.
+const descriptionLanguages = 3
+
+// descriptionCacheSize caps the cached descriptions. Expired entries are
+// swept once it is reached, and results aren't cached while it still is.
+const descriptionCacheSize = 4096
+
+// DescriptionResolver returns the current description of a tool, such as
+// one kept in a CMS, or false to use the registered one. Grouped tools are
+// named "group/name". The caller's languages are in ctx, see
+// LanguagesFromContext.
+type DescriptionResolver func(ctx context.Context, toolName string) (string, bool)
+
+// descriptionCache caches resolved descriptions, misses included, per tool
+// and caller languages.
+type descriptionCache struct {
+	mu       sync.Mutex
+	resolver DescriptionResolver
+	ttl      time.Duration
+	entries  map[string]cachedDescription
+}
+
+type cachedDescription struct {
+	text    string
+	ok      bool
+	expires time.Time
+}
+
+// SetDescriptionResolver lets tool descriptions change without
+// re-registering tools. The resolver is consulted when tools are listed or
+// fetched, and its description replaces the registered one, translations
+// included; on a miss the registered description is used. Results are
+// cached for ttl, DefaultDescriptionTTL when zero, per tool and caller
+// languages; the resolver is given only the caller's first three
+// languages. Search matches the registered descriptions.
+func (p *SimpleProvider) SetDescriptionResolver(resolver DescriptionResolver, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = DefaultDescriptionTTL
+	}
+	p.descriptions = &descriptionCache{
+		resolver: resolver,
+		ttl:      ttl,
+		entries:  make(map[string]cachedDescription),
+	}
+}
+
+// describeTool localizes a copy of a registered tool for the caller and
+// applies the description resolver, if any.
+func (p *SimpleProvider) describeTool(ctx context.Context, t *Tool) {
+	localizeTool(t, LanguagesFromContext(ctx))
+	if p.descriptions == nil {
+		return
+	}
+	if text, ok := p.descriptions.resolve(ctx, qualifiedName(t)); ok {
+		t.Description = text
+	}
+}
+
+// resolve returns the cached description of a tool, asking the resolver
+// when there is none or it has expired. The resolver runs unlocked.
+func (c *descriptionCache) resolve(ctx context.Context, toolName string) (string, bool) {
+	// Bound the languages, so the key covers everything the resolver sees
+	langs := LanguagesFromContext(ctx)
+	if len(langs) > descriptionLanguages {
+		langs = langs[:descriptionLanguages]
+		ctx = WithLanguages(ctx, langs)
+	}
+	key := toolName + "\x00" + strings.Join(langs, ",")
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	c.mu.Unlock()
+	if found && now.Before(entry.expires) {
+		return entry.text, entry.ok
+	}
+
+	text, ok := c.resolver(ctx, toolName)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, replaces := c.entries[key]; !replaces && len(c.entries) >= descriptionCacheSize {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= descriptionCacheSize {
+			return text, ok
+		}
+	}
+	c.entries[key] = cachedDescription{text: text, ok: ok, expires: now.Add(c.ttl)}
+	return text, ok
+}
@@ -0,0 +1,42 @@
+package a2t
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDescriptionCacheIsBounded(t *testing.T) {
+	calls := 0
+	var seen []string
+	p := NewSimpleProvider(NewCapabilities())
+	p.SetDescriptionResolver(func(ctx context.Context, toolName string) (string, bool) {
+		calls++
+		seen = LanguagesFromContext(ctx)
+		return "Resolved", true
+	}, time.Hour)
+	cache := p.descriptions
+
+	langs := []string{"fr", "de", "es", "it", "nl"}
+	cache.resolve(WithLanguages(context.Background(), langs), "add")
+	if len(seen) != descriptionLanguages {
+		t.Errorf("resolver saw languages %v, want the first %d", seen, descriptionLanguages)
+	}
+
+	// Languages past the first few don't make new entries
+	cache.resolve(WithLanguages(context.Background(), append(langs[:3:3], "pt")), "add")
+	if calls != 1 {
+		t.Errorf("resolver called %d times, want 1", calls)
+	}
+
+	for i := 0; i < descriptionCacheSize+100; i++ {
+		ctx := WithLanguages(context.Background(), []string{fmt.Sprintf("x-%d", i)})
+		if text, ok := cache.resolve(ctx, "add"); text != "Resolved" || !ok {
+			t.Fatalf("resolve = %q, %v", text, ok)
+		}
+	}
+	if n := len(cache.entries); n > descriptionCacheSize {
+		t.Errorf("cache holds %d entries, want at most %d", n, descriptionCacheSize)
+	}
+}
@@ -0,0 +1,170 @@
+package a2t
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of Change.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Change is one difference between two documents, at a dotted path into
+// their JSON form such as "features.groups" or
+// "tools.billing/refund.input_schema.required". Old is unset for additions
+// and New for removals.
+type Change struct {
+	Path string      `json:"path"`
+	Kind string      `json:"kind" enum:"added,removed,changed"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// CapabilitiesDiff lists the changes between two capabilities documents,
+// ordered by path.
+type CapabilitiesDiff struct {
+	Changes []Change `json:"changes"`
+}
+
+// CatalogDiff lists the changes between two catalogs, ordered by path.
+// Tools are keyed by group and name, as in "billing/refund", and groups by
+// ID.
+type CatalogDiff struct {
+	Changes []Change `json:"changes"`
+}
+
+// CompareCapabilities reports the features, endpoints, limits and other
+// fields that differ between two capabilities documents, such as those
+// served before and after an upgrade. Lists are compared as a whole.
+func CompareCapabilities(old, new *Capabilities) *CapabilitiesDiff {
+	return &CapabilitiesDiff{Changes: diffJSON(old, new)}
+}
+
+// CompareCatalogs reports the tools and groups added, removed or changed
+// between two catalog snapshots, down to the fields of each tool.
+func CompareCatalogs(old, new *CatalogSnapshot) *CatalogDiff {
+	return &CatalogDiff{Changes: diffJSON(catalogByKey(old), catalogByKey(new))}
+}
+
+// catalogByKey indexes a snapshot's tools and groups for comparison.
+func catalogByKey(snapshot *CatalogSnapshot) map[string]interface{} {
+	tools := make(map[string]interface{})
+	groups := make(map[string]interface{})
+	if snapshot != nil {
+		for i := range snapshot.Tools {
+			tools[qualifiedName(&snapshot.Tools[i])] = snapshot.Tools[i]
+		}
+		for _, group := range snapshot.Groups {
+			groups[group.ID] = group
+		}
+	}
+	return map[string]interface{}{"tools": tools, "groups": groups}
+}
+
+// Empty reports whether the documents are the same.
+func (d *CapabilitiesDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// String renders the changes one per line, for changelogs: "+" marks
+// additions, "-" removals and "~" changed values.
+func (d *CapabilitiesDiff) String() string {
+	return renderChanges(d.Changes)
+}
+
+// Empty reports whether the catalogs are the same.
+func (d *CatalogDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// String renders the changes one per line, like CapabilitiesDiff.String.
+func (d *CatalogDiff) String() string {
+	return renderChanges(d.Changes)
+}
+
+// diffJSON compares the JSON forms of two values.
+func diffJSON(old, new interface{}) []Change {
+	var a, b interface{}
+	_ = remarshal(old, &a)
+	_ = remarshal(new, &b)
+
+	var changes []Change
+	diffValues("", a, b, &changes)
+	return changes
+}
+
+// diffValues appends the changes from a to b at path, descending into
+// objects present on both sides.
+func diffValues(path string, a, b interface{}, changes *[]Change) {
+	objA, okA := a.(map[string]interface{})
+	objB, okB := b.(map[string]interface{})
+	if !okA || !okB {
+		if !equalValues(a, b) {
+			*changes = append(*changes, Change{Path: path, Kind: ChangeChanged, Old: a, New: b})
+		}
+		return
+	}
+
+	keys := make([]string, 0, len(objA)+len(objB))
+	for k := range objA {
+		keys = append(keys, k)
+	}
+	for k := range objB {
+		if _, ok := objA[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		valueA, inA := objA[k]
+		valueB, inB := objB[k]
+		switch {
+		case !inA:
+			*changes = append(*changes, Change{Path: joinPath(path, k), Kind: ChangeAdded, New: valueB})
+		case !inB:
+			*changes = append(*changes, Change{Path: joinPath(path, k), Kind: ChangeRemoved, Old: valueA})
+		default:
+			diffValues(joinPath(path, k), valueA, valueB, changes)
+		}
+	}
+}
+
+// renderChanges formats changes one per line. Added and removed objects
+// are named without their contents.
+func renderChanges(changes []Change) string {
+	var b strings.Builder
+	for _, c := range changes {
+		switch c.Kind {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "+ %s%s\n", c.Path, scalarSuffix(c.New))
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "- %s%s\n", c.Path, scalarSuffix(c.Old))
+		default:
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", c.Path, compactJSON(c.Old), compactJSON(c.New))
+		}
+	}
+	return b.String()
+}
+
+// scalarSuffix renders ": value" for values other than objects.
+func scalarSuffix(v interface{}) string {
+	if _, ok := v.(map[string]interface{}); ok {
+		return ""
+	}
+	return ": " + compactJSON(v)
+}
+
+// compactJSON encodes v on one line.
+func compactJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
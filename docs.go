@@ -0,0 +1,206 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// documentTools adds the input schema of every tool registered when the
+// server is created to the OpenAPI components, so parameter descriptions,
+// formats and patterns show up in the docs. Each schema carries an example
+// request body, so Swagger UI pre-fills "Try it out" sensibly. Read-only
+// tools are flagged with an x-a2t-read-only extension and listed on the GET
+// execute operations.
+func (s *Server) documentTools() {
+	tools, err := s.allTools()
+	if err != nil {
+		return
+	}
+
+	schemas := s.service.OpenAPICollector.Reflector().SpecEns().ComponentsEns().SchemasEns()
+	for _, tool := range tools {
+		input, _ := openAPICompatible(deepCopyMap(tool.InputSchema)).(map[string]interface{})
+		if input == nil {
+			// Tools built without NewTool may have no schema
+			input = map[string]interface{}{"type": "object"}
+		}
+		input["example"] = exampleParams(tool)
+		if tool.ReadOnly {
+			input["x-a2t-read-only"] = true
+		}
+
+		raw, err := json.Marshal(input)
+		if err != nil {
+			continue
+		}
+		var schema openapi3.SchemaOrRef
+		if err := schema.UnmarshalJSON(raw); err != nil {
+			continue
+		}
+		schemas.WithMapOfSchemaOrRefValuesItem(toolSchemaName(tool), schema)
+	}
+	s.documentReadOnly(tools)
+}
+
+// nameHeadOperations gives the HEAD twin of each GET operation the GET
+// operation's ID with a Head suffix, instead of a numbered duplicate, so
+// operation IDs don't depend on registration order.
+func (s *Server) nameHeadOperations() {
+	paths := s.service.OpenAPICollector.Reflector().SpecEns().Paths.MapOfPathItemValues
+	for path, item := range paths {
+		get, ok := item.MapOfOperationValues["get"]
+		if !ok || get.ID == nil {
+			continue
+		}
+		head, ok := item.MapOfOperationValues["head"]
+		if !ok {
+			continue
+		}
+		id := *get.ID + "Head"
+		head.ID = &id
+		item.MapOfOperationValues["head"] = head
+		paths[path] = item
+	}
+}
+
+// allTools returns the provider's whole catalog, using AllTools when the
+// provider has it and a single unbounded listing otherwise.
+func (s *Server) allTools() ([]Tool, error) {
+	if all, ok := s.provider.(interface{ AllTools() []Tool }); ok {
+		return all.AllTools(), nil
+	}
+	resp, err := s.provider.ListTools(context.Background(), "", "", 0, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Tools, nil
+}
+
+// toolSchemaName names a tool's input schema component, qualified by group.
+func toolSchemaName(tool Tool) string {
+	if tool.GroupID == "" {
+		return "ToolInput." + tool.Name
+	}
+	return "ToolInput." + tool.GroupID + "." + tool.Name
+}
+
+// openAPICompatible rewrites JSON Schema keywords OpenAPI 3.0 lacks into
+// equivalents: const into a single-value enum, and if/then/else clauses into
+// anyOf/not combinations.
+func openAPICompatible(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = openAPICompatible(item)
+		}
+		if c, ok := val["const"]; ok {
+			delete(val, "const")
+			val["enum"] = []interface{}{c}
+		}
+
+		cond, ok := val["if"]
+		if !ok {
+			return val
+		}
+		then, hasThen := val["then"]
+		otherwise, hasElse := val["else"]
+		delete(val, "if")
+		delete(val, "then")
+		delete(val, "else")
+
+		// if A then B else C == (A and B) or (not A and C)
+		if !hasThen {
+			then = map[string]interface{}{}
+		}
+		if !hasElse {
+			otherwise = map[string]interface{}{}
+		}
+		val["anyOf"] = []interface{}{
+			map[string]interface{}{"allOf": []interface{}{cond, then}},
+			map[string]interface{}{"allOf": []interface{}{map[string]interface{}{"not": cond}, otherwise}},
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = openAPICompatible(item)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// exampleParams returns the tool's explicit example, or one synthesized from
+// its schema: declared defaults, plus placeholders for required parameters
+// without a default.
+func exampleParams(tool Tool) map[string]interface{} {
+	if example, ok := tool.InputSchema["example"].(map[string]interface{}); ok {
+		return example
+	}
+	example, _ := exampleObject(tool.InputSchema).(map[string]interface{})
+	return example
+}
+
+// exampleObject builds an example object from an object schema.
+func exampleObject(schema map[string]interface{}) interface{} {
+	props, _ := schema["properties"].(map[string]interface{})
+	required, _ := stringList(schema["required"])
+
+	example := make(map[string]interface{})
+	for name, raw := range props {
+		prop, _ := raw.(map[string]interface{})
+		if def, ok := prop["default"]; ok {
+			example[name] = def
+		} else if containsString(required, name) {
+			example[name] = placeholder(prop)
+		}
+	}
+	return example
+}
+
+// placeholderFormats are example values for string formats.
+var placeholderFormats = map[string]string{
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+	"date":      "2024-01-01",
+	"date-time": "2024-01-01T00:00:00Z",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+}
+
+// placeholder returns an example value for a property schema.
+func placeholder(prop map[string]interface{}) interface{} {
+	if def, ok := prop["default"]; ok {
+		return def
+	}
+	if enum, _ := enumValues(prop); len(enum) > 0 {
+		return enum[0]
+	}
+
+	types, _ := schemaTypes(prop["type"])
+	if len(types) == 0 {
+		return "string"
+	}
+	switch types[0] {
+	case "number", "integer":
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		return []interface{}{}
+	case "object":
+		return exampleObject(prop)
+	case "null":
+		return nil
+	}
+
+	format, _ := prop["format"].(string)
+	if value, ok := placeholderFormats[format]; ok {
+		return value
+	}
+	return "string"
+}
@@ -0,0 +1,20 @@
+package a2t
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestServerAcceptsToolWithoutSchema(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	p.RegisterTool(&Tool{Name: "bare", Description: "Built without NewTool"}, echoExecutor)
+
+	h := NewServer(p).Handler()
+
+	if rec := serve(h, "GET", "/docs/openapi.json", ""); rec.Code != http.StatusOK {
+		t.Errorf("docs status %d", rec.Code)
+	}
+	if rec := serve(h, "POST", "/tools/bare", "{}"); rec.Code != http.StatusOK {
+		t.Errorf("execute status %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,98 @@
+package a2t
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrorMapper converts an executor error into an ErrorDetail. It returns nil
+// for errors it doesn't handle, which then fall back to execution_error.
+type ErrorMapper func(err error) *ErrorDetail
+
+// MapError returns an ErrorMapper that reports errors matching target, as
+// determined by errors.Is, with the given code and HTTP status. The error
+// text becomes the message.
+func MapError(target error, code string, status int) ErrorMapper {
+	return func(err error) *ErrorDetail {
+		if !errors.Is(err, target) {
+			return nil
+		}
+		return &ErrorDetail{
+			Code:    code,
+			Message: err.Error(),
+			Status:  status,
+		}
+	}
+}
+
+// ChainErrorMappers combines mappers, returning the first non-nil result.
+func ChainErrorMappers(mappers ...ErrorMapper) ErrorMapper {
+	return func(err error) *ErrorDetail {
+		for _, mapper := range mappers {
+			if mapper == nil {
+				continue
+			}
+			if detail := mapper(err); detail != nil {
+				return detail
+			}
+		}
+		return nil
+	}
+}
+
+// InvalidFields returns an invalid_params error listing the fields at fault,
+// answered with 400. Executors return it to report business-rule failures
+// per field; the provider passes it to the client unchanged.
+func InvalidFields(fields ...FieldError) *ErrorDetail {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Field
+	}
+	return &ErrorDetail{
+		Code:    "invalid_params",
+		Message: "Invalid params: " + strings.Join(names, ", "),
+		Details: fields,
+		Status:  http.StatusBadRequest,
+	}
+}
+
+// featureNotSupported reports a request for a feature the server doesn't
+// serve, answered with 501 so clients can tell it from a failure.
+func featureNotSupported(message string) *ErrorDetail {
+	return &ErrorDetail{
+		Code:    "feature_not_supported",
+		Message: message,
+		Status:  http.StatusNotImplemented,
+	}
+}
+
+// groupsNotServed reports a group request to a server without groups.
+func groupsNotServed() *ErrorDetail {
+	return featureNotSupported("Groups are not supported")
+}
+
+// errorStatuses are the HTTP statuses of error codes, used for errors that
+// don't carry a Status of their own, such as those returned in a 200
+// execute envelope.
+var errorStatuses = map[string]int{
+	"invalid_params":        http.StatusBadRequest,
+	"invalid_json":          http.StatusBadRequest,
+	"not_found":             http.StatusNotFound,
+	"tool_not_found":        http.StatusNotFound,
+	"tool_not_in_group":     http.StatusNotFound,
+	"group_not_found":       http.StatusNotFound,
+	"ambiguous_tool":        http.StatusConflict,
+	"precondition_failed":   http.StatusPreconditionFailed,
+	"feature_not_supported": http.StatusNotImplemented,
+	"tool_unavailable":      http.StatusServiceUnavailable,
+}
+
+// errorStatus returns the HTTP status for an error code, 500 for codes
+// without one.
+func errorStatus(code string) int {
+	if status, ok := errorStatuses[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
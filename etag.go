@@ -0,0 +1,71 @@
+package a2t
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// toolETag fingerprints what callers of a tool depend on: its name, group
+// and schemas. Descriptions and hints don't affect it. It is a quoted
+// strong entity tag, ready for an ETag header.
+func toolETag(t *Tool) string {
+	contract, err := json.Marshal(struct {
+		Name         string                 `json:"name"`
+		GroupID      string                 `json:"group_id"`
+		InputSchema  map[string]interface{} `json:"input_schema"`
+		OutputSchema map[string]interface{} `json:"output_schema"`
+	}{t.Name, t.GroupID, t.InputSchema, t.OutputSchema})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(contract)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// requireMatch fails with 412 unless the tool's ETag is among those listed
+// in ifMatch, an If-Match header value. "*" matches any tool. An empty
+// ifMatch always passes, and unknown tools pass through to execution, which
+// reports them as not found.
+func (s *Server) requireMatch(ctx context.Context, toolName, ifMatch string) error {
+	if ifMatch == "" {
+		return nil
+	}
+
+	getter, ok := s.provider.(ToolGetter)
+	if !ok {
+		return &ErrorDetail{
+			Code:    "precondition_failed",
+			Message: "If-Match requires a provider that can look up tools",
+			Status:  http.StatusPreconditionFailed,
+		}
+	}
+	tool, err := getter.GetTool(ctx, toolName)
+	if err != nil {
+		return nil
+	}
+	return matchETag(tool, ifMatch)
+}
+
+// matchETag checks a tool's ETag against an If-Match header value. Weak
+// tags never match, as If-Match uses strong comparison.
+func matchETag(tool *Tool, ifMatch string) error {
+	if ifMatch == "" {
+		return nil
+	}
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || (tool.ETag != "" && tag == tool.ETag) {
+			return nil
+		}
+	}
+	return &ErrorDetail{
+		Code:    "precondition_failed",
+		Message: fmt.Sprintf("Tool %s has changed; its ETag is now %s", tool.Name, tool.ETag),
+		Status:  http.StatusPreconditionFailed,
+	}
+}
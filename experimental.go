@@ -0,0 +1,64 @@
+package a2t
+
+import "net/http"
+
+// ExperimentalFeature is a preview feature, optionally served at its own
+// endpoint. It is only routed and advertised in the capabilities document
+// when experimental features are enabled.
+type ExperimentalFeature struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Method and Path locate the feature's endpoint, relative to the base
+	// path. Method defaults to GET. Features without a Handler are flags
+	// with no endpoint of their own.
+	Method  string       `json:"method,omitempty"`
+	Path    string       `json:"path,omitempty"`
+	Handler http.Handler `json:"-"`
+}
+
+// WithExperimental declares preview features. They stay hidden unless
+// enabled with WithExperimentalEnabled.
+func WithExperimental(features ...ExperimentalFeature) ServerOption {
+	return func(s *Server) {
+		s.experimental = append(s.experimental, features...)
+	}
+}
+
+// WithExperimentalEnabled turns experimental features on or off. When off,
+// the default, their routes aren't registered and the capabilities document
+// omits them.
+func WithExperimentalEnabled(enabled bool) ServerOption {
+	return func(s *Server) {
+		s.experimentalEnabled = enabled
+	}
+}
+
+// experimentalFeatures returns the enabled preview features with endpoint
+// methods defaulted and paths mounted under the base path.
+func (s *Server) experimentalFeatures() []ExperimentalFeature {
+	if !s.experimentalEnabled {
+		return nil
+	}
+
+	features := make([]ExperimentalFeature, 0, len(s.experimental))
+	for _, feature := range s.experimental {
+		if feature.Handler != nil {
+			if feature.Method == "" {
+				feature.Method = http.MethodGet
+			}
+			feature.Path = s.path(feature.Path)
+		}
+		features = append(features, feature)
+	}
+	return features
+}
+
+// registerExperimental routes the endpoints of enabled preview features.
+func (s *Server) registerExperimental() {
+	for _, feature := range s.experimentalFeatures() {
+		if feature.Handler != nil {
+			s.service.Method(feature.Method, feature.Path, feature.Handler)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+// ExportContentType is the media type of catalog exports.
+const ExportContentType = "application/x-ndjson"
+
+// ExportToolsInput represents input for exporting the catalog.
+type ExportToolsInput struct {
+	Groups bool `query:"groups" description:"Interleave group records with the tools, each line tagged with a type"`
+}
+
+// exportOutput streams the export to the response.
+type exportOutput struct {
+	usecase.OutputWithEmbeddedWriter
+}
+
+// exportedTool and exportedGroup are the lines of an export with groups.
+type exportedTool struct {
+	Type string `json:"type"`
+	Tool
+}
+
+type exportedGroup struct {
+	Type string `json:"type"`
+	Group
+}
+
+// exportRoute registers the export endpoint at pattern.
+func (s *Server) exportRoute(pattern string) {
+	s.service.Get(pattern, s.exportToolsUsecase(), nethttp.SuccessfulResponseContentType(ExportContentType))
+}
+
+// exportToolsUsecase streams the whole catalog as JSON Lines.
+func (s *Server) exportToolsUsecase() usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input ExportToolsInput, output *exportOutput) error {
+		var groups []Group
+		if input.Groups {
+			groupProvider, ok := s.provider.(GroupProvider)
+			if !ok || !s.provider.GetCapabilities().Features.Groups {
+				return groupsNotServed()
+			}
+			resp, err := groupProvider.ListGroups(ctx, "", "", 0, 0)
+			if err != nil {
+				return err
+			}
+			groups = resp.Groups
+		}
+
+		// Group order keeps a group's tools together, ungrouped tools first
+		resp, err := s.provider.ListTools(WithListOptions(ctx, ListOptions{Sort: "group"}), "", "", 0, 0)
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(output.Writer)
+		if !input.Groups {
+			for _, tool := range resp.Tools {
+				if err := enc.Encode(tool); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return exportWithGroups(enc, resp.Tools, groups)
+	})
+
+	u.SetName("exportTools")
+	u.SetTags("Tools")
+	u.SetTitle("Export Tools")
+	u.SetDescription("Streams every tool as JSON Lines, one tool per line, ordered by group and name. " +
+		"With groups=true, each group's record precedes its tools and every line carries a type of tool or group.")
+	u.SetExpectedErrors(status.Unimplemented)
+
+	return u
+}
+
+// exportWithGroups writes the ungrouped tools, then each group followed by
+// its tools. tools must be sorted by group.
+func exportWithGroups(enc *json.Encoder, tools []Tool, groups []Group) error {
+	i := 0
+	writeTools := func(groupID string) error {
+		for ; i < len(tools) && tools[i].GroupID == groupID; i++ {
+			if err := enc.Encode(exportedTool{Type: "tool", Tool: tools[i]}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := writeTools(""); err != nil {
+		return err
+	}
+	for _, group := range groups {
+		// Skip tools of groups missing from the listing
+		for i < len(tools) && tools[i].GroupID < group.ID {
+			i++
+		}
+		if err := enc.Encode(exportedGroup{Type: "group", Group: group}); err != nil {
+			return err
+		}
+		if err := writeTools(group.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isExport reports whether r is for the export endpoint, which streams and
+// so is never buffered for compression.
+func (s *Server) isExport(r *http.Request) bool {
+	return r.URL.Path == s.path(s.provider.GetCapabilities().Endpoints.Tools+"/export")
+}
@@ -3,17 +3,18 @@ module github.com/traego/a2t
 go 1.21
 
 require (
+	github.com/go-chi/chi/v5 v5.2.2
 	github.com/swaggest/openapi-go v0.2.60
 	github.com/swaggest/rest v0.2.75
 	github.com/swaggest/swgui v1.8.1
+	github.com/swaggest/usecase v1.3.1
 )
 
 require (
-	github.com/go-chi/chi/v5 v5.2.2 // indirect
 	github.com/santhosh-tekuri/jsonschema/v3 v3.1.0 // indirect
 	github.com/swaggest/form/v5 v5.1.1 // indirect
 	github.com/swaggest/jsonschema-go v0.3.78 // indirect
 	github.com/swaggest/refl v1.4.0 // indirect
-	github.com/swaggest/usecase v1.3.1 // indirect
+	github.com/vearutop/statigz v1.4.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
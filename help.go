@@ -0,0 +1,198 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+// HelpContentType is the media type of tool help.
+const HelpContentType = "text/markdown; charset=utf-8"
+
+// GetToolHelpInput represents input for reading a tool's help.
+type GetToolHelpInput struct {
+	Name string `path:"name" description:"Tool name"`
+}
+
+// GetGroupToolHelpInput represents input for reading the help of a tool in
+// a group.
+type GetGroupToolHelpInput struct {
+	ID   string `path:"id" description:"Group ID"`
+	Name string `path:"name" description:"Tool name"`
+}
+
+// helpOutput streams the help to the response.
+type helpOutput struct {
+	usecase.OutputWithEmbeddedWriter
+}
+
+// Help renders the tool for people as Markdown: its description, each
+// parameter with its type, whether it's required, its description and
+// constraints, and an example request. The Markdown reads as plain text
+// too, for terminals.
+func (t *Tool) Help() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", t.Name)
+	if t.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", t.Description)
+	}
+	if t.ReadOnly {
+		b.WriteString("\nRead-only: safe to call speculatively.\n")
+	}
+
+	props, _ := t.InputSchema["properties"].(map[string]interface{})
+	if len(props) > 0 {
+		required, _ := stringList(t.InputSchema["required"])
+		b.WriteString("\n## Parameters\n\n")
+		for _, name := range helpOrder(props, required) {
+			prop, _ := props[name].(map[string]interface{})
+			writeParamHelp(&b, name, prop, containsString(required, name), t.aliasesOf(name))
+		}
+	}
+
+	if example := exampleParams(*t); len(example) > 0 {
+		data, err := json.MarshalIndent(example, "", "  ")
+		if err == nil {
+			fmt.Fprintf(&b, "\n## Example\n\n```json\n%s\n```\n", data)
+		}
+	}
+	return b.String()
+}
+
+// helpOrder lists required parameters first, then the others, each
+// alphabetically.
+func helpOrder(props map[string]interface{}, required []string) []string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, rj := containsString(required, names[i]), containsString(required, names[j])
+		if ri != rj {
+			return ri
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// writeParamHelp writes one parameter's line.
+func writeParamHelp(b *strings.Builder, name string, prop map[string]interface{}, required bool, aliases []string) {
+	types, _ := schemaTypes(prop["type"])
+	typ := strings.Join(types, " or ")
+	if typ == "" {
+		typ = "any"
+	}
+	need := "optional"
+	if required {
+		need = "required"
+	}
+	fmt.Fprintf(b, "- `%s` (%s, %s)", name, typ, need)
+
+	var notes []string
+	if desc, _ := prop["description"].(string); desc != "" {
+		notes = append(notes, strings.TrimSuffix(desc, ".")+".")
+	}
+	if format, ok := prop["format"].(string); ok {
+		notes = append(notes, "Format: "+format+".")
+	}
+	if pattern, ok := prop["pattern"].(string); ok {
+		notes = append(notes, "Pattern: `"+pattern+"`.")
+	}
+	if enum, _ := enumValues(prop); len(enum) > 0 {
+		values := make([]string, len(enum))
+		for i, v := range enum {
+			values[i] = compactJSON(v)
+		}
+		notes = append(notes, "One of: "+strings.Join(values, ", ")+".")
+	}
+	if def, ok := prop["default"]; ok {
+		notes = append(notes, "Default: "+compactJSON(def)+".")
+	}
+	if len(aliases) > 0 {
+		notes = append(notes, "Also accepted as `"+strings.Join(aliases, "`, `")+"`.")
+	}
+	if len(notes) > 0 {
+		b.WriteString(": " + strings.Join(notes, " "))
+	}
+	b.WriteString("\n")
+}
+
+// aliasesOf returns the aliases of a parameter, sorted.
+func (t *Tool) aliasesOf(name string) []string {
+	var aliases []string
+	for alias, canonical := range t.aliases {
+		if canonical == name {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// toolHelpUsecase serves a tool's help.
+func (s *Server) toolHelpUsecase() usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, in GetToolHelpInput, output *helpOutput) error {
+		return s.writeHelp(ctx, in.Name, output.Writer)
+	})
+
+	u.SetName("getToolHelp")
+	u.SetTags("Tools")
+	u.SetTitle("Get Tool Help")
+	u.SetDescription("Describes a tool for people, as Markdown: its purpose, each parameter and an example request. " +
+		"Descriptions follow Accept-Language.")
+	u.SetExpectedErrors(status.NotFound)
+
+	return u
+}
+
+// groupToolHelpUsecase serves the help of a tool in a group.
+func (s *Server) groupToolHelpUsecase() usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, in GetGroupToolHelpInput, output *helpOutput) error {
+		return s.writeHelp(WithGroupID(ctx, in.ID), in.Name, output.Writer)
+	})
+
+	u.SetName("getGroupToolHelp")
+	u.SetTags("Groups")
+	u.SetTitle("Get Group Tool Help")
+	u.SetDescription("Describes a tool in a group for people, as Markdown, like the tool help endpoint")
+	u.SetExpectedErrors(status.NotFound)
+
+	return u
+}
+
+// helpRoute registers a help endpoint at pattern.
+func (s *Server) helpRoute(pattern string, u usecase.Interactor) {
+	s.service.Get(pattern, u, nethttp.SuccessfulResponseContentType(HelpContentType))
+}
+
+// writeHelp writes the help of the named tool, localized for the caller.
+func (s *Server) writeHelp(ctx context.Context, name string, w io.Writer) error {
+	getter, ok := s.provider.(ToolGetter)
+	if !ok {
+		return featureNotSupported("Tool help is not supported by this provider")
+	}
+	tool, err := getter.GetTool(ctx, name)
+	if err != nil {
+		return toolNotFound(err)
+	}
+	_, err = io.WriteString(w, tool.Help())
+	return err
+}
+
+// toolNotFound answers tool_not_found and tool_not_in_group errors with 404.
+func toolNotFound(err error) error {
+	var detail *ErrorDetail
+	if errors.As(err, &detail) && (detail.Code == "tool_not_found" || detail.Code == "tool_not_in_group") {
+		return status.Wrap(err, status.NotFound)
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package a2t
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Highlight is a span of a tool field that matched the search query, as
+// byte offsets into the field's UTF-8 text.
+type Highlight struct {
+	Field string `json:"field" enum:"name,description"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// highlightTool returns the spans of the tool's name and description that
+// match the query, limited to the searched fields.
+func highlightTool(tool *Tool, query string, fields []string) []Highlight {
+	if len(fields) == 0 {
+		fields = []string{"name", "description"}
+	}
+
+	var highlights []Highlight
+	for _, field := range fields {
+		var text string
+		switch field {
+		case "name":
+			text = tool.Name
+		case "description":
+			text = tool.Description
+		default:
+			continue
+		}
+		for _, span := range matchSpans(text, query) {
+			highlights = append(highlights, Highlight{Field: field, Start: span[0], End: span[1]})
+		}
+	}
+	return highlights
+}
+
+// matchSpans returns the non-overlapping case-insensitive occurrences of
+// query in text.
+func matchSpans(text, query string) [][2]int {
+	n := utf8.RuneCountInString(query)
+	if n == 0 {
+		return nil
+	}
+
+	var spans [][2]int
+	for i := 0; i < len(text); {
+		j := i
+		for k := 0; k < n && j < len(text); k++ {
+			_, size := utf8.DecodeRuneInString(text[j:])
+			j += size
+		}
+		if strings.EqualFold(text[i:j], query) {
+			spans = append(spans, [2]int{i, j})
+			i = j
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	return spans
+}
@@ -0,0 +1,126 @@
+package a2t
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WithLocalizedDescription adds a description in the given language (a BCP 47
+// tag such as "fr" or "pt-BR"), returned to callers preferring it through
+// Accept-Language. Description remains the default.
+func (t *Tool) WithLocalizedDescription(lang, description string) *Tool {
+	if t.descriptions == nil {
+		t.descriptions = make(map[string]string)
+	}
+	t.descriptions[strings.ToLower(lang)] = description
+	return t
+}
+
+// WithLocalizedName adds a group name in the given language.
+func (g *Group) WithLocalizedName(lang, name string) *Group {
+	if g.names == nil {
+		g.names = make(map[string]string)
+	}
+	g.names[strings.ToLower(lang)] = name
+	return g
+}
+
+// WithLocalizedDescription adds a group description in the given language.
+func (g *Group) WithLocalizedDescription(lang, description string) *Group {
+	if g.descriptions == nil {
+		g.descriptions = make(map[string]string)
+	}
+	g.descriptions[strings.ToLower(lang)] = description
+	return g
+}
+
+// localizeTool replaces the tool's description with the best match for the
+// caller's languages.
+func localizeTool(t *Tool, langs []string) {
+	if text, ok := localized(t.descriptions, langs); ok {
+		t.Description = text
+	}
+}
+
+// localizeGroup replaces the group's name and description with the best
+// matches for the caller's languages.
+func localizeGroup(g *Group, langs []string) {
+	if text, ok := localized(g.names, langs); ok {
+		g.Name = text
+	}
+	if text, ok := localized(g.descriptions, langs); ok {
+		g.Description = text
+	}
+}
+
+// localized picks a translation for the first language that has one,
+// falling back from a regional tag ("fr-ca") to its base language ("fr").
+func localized(translations map[string]string, langs []string) (string, bool) {
+	if len(translations) == 0 {
+		return "", false
+	}
+	for _, lang := range langs {
+		if text, ok := translations[lang]; ok {
+			return text, true
+		}
+		if base, _, found := strings.Cut(lang, "-"); found {
+			if text, ok := translations[base]; ok {
+				return text, true
+			}
+		}
+	}
+	return "", false
+}
+
+// acceptLanguage stores the caller's preferred languages in the request
+// context.
+func (s *Server) acceptLanguage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header := r.Header.Get("Accept-Language"); header != "" {
+			r = r.WithContext(WithLanguages(r.Context(), parseAcceptLanguage(header)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseAcceptLanguage returns the lowercased language tags of an
+// Accept-Language header in order of preference, dropping "*" and q=0 entries.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				parsed, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil {
+					parsed = 0
+				}
+				q = parsed
+			}
+		}
+		if tag == "" || tag == "*" || q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag, q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	langs := make([]string, len(tags))
+	for i, t := range tags {
+		langs[i] = t.tag
+	}
+	return langs
+}
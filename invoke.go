@@ -0,0 +1,40 @@
+package a2t
+
+import "context"
+
+// Invoke executes a tool in-process through the same pipeline as
+// POST /tools/{name}: scope checks, concurrency limits, parameter defaults and
+// validation, hooks and auditing. It returns the ExecuteResponse a client
+// would receive; errors a client would get as an HTTP error status (such as
+// insufficient_scope or overloaded) are returned as an error wrapping an
+// *ErrorDetail.
+//
+// Attach caller claims with WithClaims. A request ID is generated unless ctx
+// already carries one.
+func (s *Server) Invoke(ctx context.Context, name string, params map[string]interface{}) (*ExecuteResponse, error) {
+	output := &ExecuteResponse{}
+	err := s.executeToolUsecase().Interact(invokeContext(ctx), ExecuteToolInput{Name: name, Params: params}, output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// InvokeGroup executes a tool within a group in-process, like
+// POST /groups/{id}/tools/{name}. See Invoke.
+func (s *Server) InvokeGroup(ctx context.Context, groupID, name string, params map[string]interface{}) (*ExecuteResponse, error) {
+	output := &ExecuteResponse{}
+	err := s.executeGroupToolUsecase().Interact(invokeContext(ctx), ExecuteGroupToolInput{ID: groupID, Name: name, Params: params}, output)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// invokeContext gives an in-process call the request ID the HTTP path would.
+func invokeContext(ctx context.Context) context.Context {
+	if RequestIDFromContext(ctx) == "" {
+		ctx = WithRequestID(ctx, newRequestID())
+	}
+	return ctx
+}
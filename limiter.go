@@ -0,0 +1,282 @@
+package a2t
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/swaggest/usecase/status"
+)
+
+// Overload behaviors advertised as limits.overload_behavior.
+const (
+	// OverloadQueue makes calls wait up to QueueTimeout for a free slot.
+	OverloadQueue = "queue"
+
+	// OverloadReject fails calls as soon as no slot is free.
+	OverloadReject = "reject"
+)
+
+// Backpressure headers sent with overloaded responses.
+const (
+	QueueDepthHeader = "X-A2T-Queue-Depth"
+
+	// DefaultRetryAfter is the Retry-After advice when LimitsConfig leaves
+	// RetryAfter unset.
+	DefaultRetryAfter = time.Second
+)
+
+// executionLimiter bounds the number of in-flight executions, globally and
+// per tool. A nil limiter imposes no limits.
+type executionLimiter struct {
+	global        chan struct{}
+	perTool       int
+	timeout       time.Duration
+	maxQueueDepth int
+
+	// waiting counts calls queued for a slot.
+	waiting atomic.Int64
+
+	mu    sync.Mutex
+	tools map[toolKey]chan struct{}
+}
+
+// newExecutionLimiter returns a limiter for the configured limits, or nil
+// when no concurrency limit is set.
+func newExecutionLimiter(limits *LimitsConfig) *executionLimiter {
+	if limits == nil || (limits.MaxConcurrentExecutions <= 0 && limits.MaxConcurrentPerTool <= 0) {
+		return nil
+	}
+
+	l := &executionLimiter{
+		perTool:       limits.MaxConcurrentPerTool,
+		timeout:       limits.QueueTimeout,
+		maxQueueDepth: limits.MaxQueueDepth,
+		tools:         make(map[toolKey]chan struct{}),
+	}
+	if limits.OverloadBehavior == OverloadReject {
+		l.timeout = 0
+	}
+	if limits.MaxConcurrentExecutions > 0 {
+		l.global = make(chan struct{}, limits.MaxConcurrentExecutions)
+	}
+	return l
+}
+
+// withOverrides returns a limiter applying the set fields of limits on top
+// of l. Executions share l's global slots unless limits caps them itself.
+func (l *executionLimiter) withOverrides(limits *LimitsConfig) *executionLimiter {
+	o := &executionLimiter{tools: make(map[toolKey]chan struct{})}
+	if l != nil {
+		o.global, o.perTool, o.timeout, o.maxQueueDepth = l.global, l.perTool, l.timeout, l.maxQueueDepth
+	}
+
+	if limits.MaxConcurrentExecutions > 0 {
+		o.global = make(chan struct{}, limits.MaxConcurrentExecutions)
+	}
+	if limits.MaxConcurrentPerTool > 0 {
+		o.perTool = limits.MaxConcurrentPerTool
+	}
+	if limits.QueueTimeout > 0 {
+		o.timeout = limits.QueueTimeout
+	}
+	if limits.MaxQueueDepth > 0 {
+		o.maxQueueDepth = limits.MaxQueueDepth
+	}
+	if limits.OverloadBehavior == OverloadReject {
+		o.timeout = 0
+	}
+	return o
+}
+
+// groupLimiter is the limiter built for a group's limits.
+type groupLimiter struct {
+	limits  *LimitsConfig
+	limiter *executionLimiter
+}
+
+// groupLimiterKey identifies a group's limiter on top of a tier's.
+type groupLimiterKey struct {
+	groupID string
+	base    *executionLimiter
+}
+
+// limiterFor returns the limiter for executions within a group: one built
+// from the group's own limits when it has any, the caller's tier's
+// otherwise. A group's limiter is rebuilt when its limits are replaced.
+func (s *Server) limiterFor(ctx context.Context, groupID string) *executionLimiter {
+	base := s.tierLimiter(ctx)
+	groupProvider, ok := s.provider.(GroupProvider)
+	if !ok || groupID == "" {
+		return base
+	}
+	group, err := groupProvider.GetGroup(ctx, groupID)
+	if err != nil || group.Limits == nil {
+		return base
+	}
+
+	s.groupLimitersMu.Lock()
+	defer s.groupLimitersMu.Unlock()
+
+	key := groupLimiterKey{groupID: groupID, base: base}
+	cached, ok := s.groupLimiters[key]
+	if !ok || cached.limits != group.Limits {
+		cached = groupLimiter{limits: group.Limits, limiter: base.withOverrides(group.Limits)}
+		s.groupLimiters[key] = cached
+	}
+	return cached.limiter
+}
+
+// acquire takes a slot for the tool, waiting up to the queue timeout
+// when the limit is reached. The returned release func must be called once
+// the execution finishes.
+func (l *executionLimiter) acquire(ctx context.Context, groupID, toolName string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	var deadline <-chan time.Time
+	if l.timeout > 0 {
+		timer := time.NewTimer(l.timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	// Take the tool slot first so a queued call doesn't hold a global slot
+	var held []chan struct{}
+	release := func() {
+		for _, sem := range held {
+			<-sem
+		}
+	}
+
+	for _, sem := range []chan struct{}{l.toolSlots(toolKey{groupID: groupID, name: toolName}), l.global} {
+		if sem == nil {
+			continue
+		}
+		if err := l.wait(ctx, sem, deadline, toolName); err != nil {
+			release()
+			return nil, err
+		}
+		held = append(held, sem)
+	}
+	return release, nil
+}
+
+// wait takes a slot from sem, rejecting immediately when no queue timeout
+// is configured or the queue is full.
+func (l *executionLimiter) wait(ctx context.Context, sem chan struct{}, deadline <-chan time.Time, toolName string) error {
+	select {
+	case sem <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if deadline == nil {
+		return overloaded(toolName)
+	}
+	if depth := l.waiting.Add(1); l.maxQueueDepth > 0 && depth > int64(l.maxQueueDepth) {
+		l.waiting.Add(-1)
+		return overloaded(toolName)
+	}
+	defer l.waiting.Add(-1)
+
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-deadline:
+		return overloaded(toolName)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// toolSlots returns the semaphore for a tool, creating it on first use.
+func (l *executionLimiter) toolSlots(key toolKey) chan struct{} {
+	if l.perTool <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sem, ok := l.tools[key]
+	if !ok {
+		sem = make(chan struct{}, l.perTool)
+		l.tools[key] = sem
+	}
+	return sem
+}
+
+// overloaded returns the error reported when no execution slot is free.
+func overloaded(toolName string) error {
+	return status.Wrap(&ErrorDetail{
+		Code:    "overloaded",
+		Message: "Too many concurrent executions, try again later: " + toolName,
+	}, status.Unavailable)
+}
+
+// queueDepth returns the number of calls waiting for a slot.
+func (l *executionLimiter) queueDepth() int64 {
+	if l == nil {
+		return 0
+	}
+	return l.waiting.Load()
+}
+
+// backpressure advises clients of overloaded execution routes when to come
+// back: 503 responses get a Retry-After header and the depth of the
+// execution queue in X-A2T-Queue-Depth.
+func (s *Server) backpressure(next http.Handler) http.Handler {
+	if s.limiter == nil && !s.hasGroupLimits() && s.tiers == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&backpressureWriter{ResponseWriter: w, server: s, r: r}, r)
+	})
+}
+
+// hasGroupLimits reports whether a group provider may carry group limits.
+func (s *Server) hasGroupLimits() bool {
+	_, ok := s.provider.(GroupProvider)
+	return ok
+}
+
+// backpressureWriter adds backpressure headers when a 503 is written.
+type backpressureWriter struct {
+	http.ResponseWriter
+	server *Server
+	r      *http.Request
+}
+
+func (b *backpressureWriter) WriteHeader(code int) {
+	if code == http.StatusServiceUnavailable {
+		h := b.Header()
+		if h.Get("Retry-After") == "" {
+			h.Set("Retry-After", strconv.Itoa(int(math.Ceil(b.server.retryAfter(b.r.Context()).Seconds()))))
+		}
+		limiter := b.server.limiterFor(b.r.Context(), chi.URLParam(b.r, "id"))
+		h.Set(QueueDepthHeader, strconv.FormatInt(limiter.queueDepth(), 10))
+	}
+	b.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap returns the underlying ResponseWriter.
+func (b *backpressureWriter) Unwrap() http.ResponseWriter {
+	return b.ResponseWriter
+}
+
+// retryAfter returns the Retry-After advice configured for the caller in ctx.
+func (s *Server) retryAfter(ctx context.Context) time.Duration {
+	if limits := s.capabilitiesFor(ctx).Limits; limits != nil && limits.RetryAfter > 0 {
+		return limits.RetryAfter
+	}
+	return DefaultRetryAfter
+}
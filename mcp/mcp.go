@@ -0,0 +1,343 @@
+// Package mcp exposes an a2t ToolProvider over the Model Context Protocol.
+//
+// The bridge speaks newline-delimited JSON-RPC 2.0 over stdio and implements
+// the initialize, ping, tools/list and tools/call methods. Groups are
+// flattened: every tool the provider lists is exposed as a top-level MCP tool,
+// named "group.name" when it belongs to a group so that tools sharing a name
+// across groups stay distinct.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/traego/a2t"
+)
+
+// ProtocolVersion is the MCP protocol revision the bridge implements.
+const ProtocolVersion = "2024-11-05"
+
+// JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Request is a JSON-RPC request or notification.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC response.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Tool is an MCP tool definition.
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	Annotations *ToolAnnotations       `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations are MCP hints about a tool's behavior.
+type ToolAnnotations struct {
+	ReadOnlyHint bool `json:"readOnlyHint,omitempty"`
+}
+
+// Content is an MCP content item in a tool result.
+type Content struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	URI      string `json:"uri,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// CallToolResult is the result of tools/call.
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// Server bridges a ToolProvider to MCP clients.
+type Server struct {
+	provider a2t.ToolProvider
+	name     string
+	version  string
+}
+
+// NewServer creates an MCP bridge for the provider.
+func NewServer(provider a2t.ToolProvider) *Server {
+	return &Server{
+		provider: provider,
+		name:     "a2t",
+		version:  provider.GetCapabilities().Version,
+	}
+}
+
+// WithServerInfo sets the name and version reported during initialize.
+func (s *Server) WithServerInfo(name, version string) *Server {
+	s.name = name
+	s.version = version
+	return s
+}
+
+// ServeStdio serves MCP over the process's stdin and stdout.
+func (s *Server) ServeStdio(ctx context.Context) error {
+	return s.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes
+// responses to w until r is exhausted or ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	enc := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var resp *Response
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			resp = errorResponse(nil, CodeParseError, "Parse error: "+err.Error())
+		} else {
+			resp = s.Handle(ctx, &req)
+		}
+
+		if resp == nil {
+			continue
+		}
+
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// Handle processes a single request. It returns nil for notifications.
+func (s *Server) Handle(ctx context.Context, req *Request) *Response {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, CodeInvalidRequest, "Invalid request")
+	}
+
+	// Notifications carry no ID and never get a response
+	if len(req.ID) == 0 {
+		return nil
+	}
+
+	var result interface{}
+	var rpcErr *Error
+
+	switch req.Method {
+	case "initialize":
+		result = s.initialize(req.Params)
+	case "ping":
+		result = struct{}{}
+	case "tools/list":
+		result, rpcErr = s.listTools(ctx)
+	case "tools/call":
+		result, rpcErr = s.callTool(ctx, req.Params)
+	default:
+		rpcErr = &Error{Code: CodeMethodNotFound, Message: "Method not found: " + req.Method}
+	}
+
+	if rpcErr != nil {
+		return &Response{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+	return &Response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// initialize answers the MCP handshake, echoing the client's protocol
+// version when one is given.
+func (s *Server) initialize(raw json.RawMessage) interface{} {
+	var params struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	_ = json.Unmarshal(raw, &params)
+
+	version := params.ProtocolVersion
+	if version == "" {
+		version = ProtocolVersion
+	}
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools": map[string]interface{}{},
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    s.name,
+			"version": s.version,
+		},
+	}
+}
+
+// listTools returns every provider tool as an MCP tool, flattening groups.
+func (s *Server) listTools(ctx context.Context) (interface{}, *Error) {
+	resp, err := s.provider.ListTools(ctx, "", "", 0, 0)
+	if err != nil {
+		return nil, &Error{Code: CodeInternalError, Message: err.Error()}
+	}
+
+	tools := make([]Tool, 0, len(resp.Tools))
+	for _, t := range resp.Tools {
+		tools = append(tools, ToMCPTool(t))
+	}
+	return map[string]interface{}{"tools": tools}, nil
+}
+
+// callTool executes a tool and translates the a2t response.
+func (s *Server) callTool(ctx context.Context, raw json.RawMessage) (interface{}, *Error) {
+	var params struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+	}
+	if err := json.Unmarshal(raw, &params); err != nil || params.Name == "" {
+		return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params: tool name is required"}
+	}
+	if params.Arguments == nil {
+		params.Arguments = make(map[string]interface{})
+	}
+
+	resp, err := s.execute(ctx, params.Name, params.Arguments)
+	if err != nil {
+		return nil, &Error{Code: CodeInternalError, Message: err.Error()}
+	}
+	if resp.Error != nil && resp.Error.Code == "tool_not_found" {
+		return nil, &Error{Code: CodeInvalidParams, Message: resp.Error.Message}
+	}
+
+	result, err := ToMCPResult(resp)
+	if err != nil {
+		return nil, &Error{Code: CodeInternalError, Message: err.Error()}
+	}
+	return result, nil
+}
+
+// execute runs the named tool. Names not found as given are retried as a
+// qualified "group.name" through the group, when the provider has groups.
+func (s *Server) execute(ctx context.Context, name string, args map[string]interface{}) (*a2t.ExecuteResponse, error) {
+	resp, err := s.provider.ExecuteTool(ctx, name, args)
+	if err != nil || resp.Error == nil || resp.Error.Code != "tool_not_found" {
+		return resp, err
+	}
+
+	groups, ok := s.provider.(a2t.GroupProvider)
+	i := strings.LastIndex(name, ".")
+	if !ok || i <= 0 || i == len(name)-1 {
+		return resp, nil
+	}
+	grouped, err := groups.ExecuteGroupTool(ctx, name[:i], name[i+1:], args)
+	if err != nil {
+		return nil, err
+	}
+	if grouped.Error != nil {
+		switch grouped.Error.Code {
+		case "tool_not_found", "tool_not_in_group", "group_not_found":
+			return resp, nil
+		}
+	}
+	return grouped, nil
+}
+
+// ToMCPTool converts an a2t tool to an MCP tool definition. Grouped tools
+// are named "group.name".
+func ToMCPTool(t a2t.Tool) Tool {
+	name := t.Name
+	if t.GroupID != "" {
+		name = t.GroupID + "." + t.Name
+	}
+	tool := Tool{
+		Name:        name,
+		Description: t.Description,
+		InputSchema: t.InputSchema,
+	}
+	if t.ReadOnly {
+		tool.Annotations = &ToolAnnotations{ReadOnlyHint: true}
+	}
+	return tool
+}
+
+// ToMCPResult converts an a2t execute response to an MCP tool result.
+// Execution errors are reported in-band with isError set, as MCP expects.
+func ToMCPResult(resp *a2t.ExecuteResponse) (*CallToolResult, error) {
+	if resp.Error != nil {
+		return &CallToolResult{
+			Content: []Content{{Type: "text", Text: resp.Error.Message}},
+			IsError: true,
+		}, nil
+	}
+
+	if len(resp.Content) > 0 {
+		content := make([]Content, 0, len(resp.Content))
+		for _, block := range resp.Content {
+			content = append(content, Content{
+				Type:     block.Type,
+				Text:     block.Text,
+				Data:     block.Data,
+				MimeType: block.MimeType,
+				URI:      block.URI,
+				Name:     block.Name,
+			})
+		}
+		return &CallToolResult{Content: content}, nil
+	}
+
+	text, ok := resp.Result.(string)
+	if !ok {
+		b, err := json.Marshal(resp.Result)
+		if err != nil {
+			return nil, fmt.Errorf("encoding result: %w", err)
+		}
+		text = string(b)
+	}
+	return &CallToolResult{
+		Content: []Content{{Type: "text", Text: text}},
+	}, nil
+}
+
+// errorResponse creates a JSON-RPC error response.
+func errorResponse(id json.RawMessage, code int, message string) *Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &Response{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &Error{Code: code, Message: message},
+	}
+}
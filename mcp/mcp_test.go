@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/traego/a2t"
+)
+
+func TestGroupedToolsAreQualified(t *testing.T) {
+	p := a2t.NewGroupProvider(a2t.NewCapabilities().WithGroups(""))
+	for _, group := range []string{"billing", "reports"} {
+		group := group
+		p.RegisterGroup(a2t.NewGroup(group, group, group+" tools"))
+		p.RegisterTool(a2t.NewTool("export", "Export data").WithGroup(group),
+			func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				return group, nil
+			})
+	}
+	s := NewServer(p)
+
+	resp := s.Handle(context.Background(), &Request{JSONRPC: "2.0", ID: json.RawMessage("1"), Method: "tools/list"})
+	if resp.Error != nil {
+		t.Fatalf("tools/list: %+v", resp.Error)
+	}
+	names := make(map[string]bool)
+	for _, tool := range resp.Result.(map[string]interface{})["tools"].([]Tool) {
+		names[tool.Name] = true
+	}
+	if len(names) != 2 || !names["billing.export"] || !names["reports.export"] {
+		t.Fatalf("tool names = %v, want billing.export and reports.export", names)
+	}
+
+	for _, group := range []string{"billing", "reports"} {
+		params, _ := json.Marshal(map[string]interface{}{"name": group + ".export"})
+		resp := s.Handle(context.Background(), &Request{JSONRPC: "2.0", ID: json.RawMessage("2"), Method: "tools/call", Params: params})
+		if resp.Error != nil {
+			t.Fatalf("tools/call %s.export: %+v", group, resp.Error)
+		}
+		result := resp.Result.(*CallToolResult)
+		if result.IsError || result.Content[0].Text != group {
+			t.Errorf("tools/call %s.export = %+v, want %q", group, result, group)
+		}
+	}
+
+	params, _ := json.Marshal(map[string]interface{}{"name": "missing.export"})
+	resp = s.Handle(context.Background(), &Request{JSONRPC: "2.0", ID: json.RawMessage("3"), Method: "tools/call", Params: params})
+	if resp.Error == nil || resp.Error.Code != CodeInvalidParams {
+		t.Errorf("tools/call missing.export error = %+v, want invalid params", resp.Error)
+	}
+}
@@ -0,0 +1,73 @@
+package a2t
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultAcceptedMediaTypes are the request body types execution endpoints
+// accept unless overridden with WithAcceptedMediaTypes.
+var DefaultAcceptedMediaTypes = []string{
+	"application/json",
+	"application/x-www-form-urlencoded",
+	"multipart/form-data",
+}
+
+// WithAcceptedMediaTypes replaces the Content-Type values accepted on POST
+// requests with a body. Others are rejected with 415.
+func WithAcceptedMediaTypes(types ...string) ServerOption {
+	return func(s *Server) {
+		s.acceptedMediaTypes = types
+	}
+}
+
+// enforceMediaType rejects POST requests whose non-empty body has a missing
+// or unaccepted Content-Type, so a mislabeled body isn't silently ignored.
+// Empty-body POSTs pass through.
+func (s *Server) enforceMediaType(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !hasBody(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		for _, accepted := range s.acceptedMediaTypes {
+			if strings.EqualFold(mediaType, accepted) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		message := "Content-Type is required for a request body; use one of " + strings.Join(s.acceptedMediaTypes, ", ")
+		if mediaType != "" {
+			message = "Unsupported Content-Type " + mediaType + "; use one of " + strings.Join(s.acceptedMediaTypes, ", ")
+		}
+		writeError(w, r, http.StatusUnsupportedMediaType, &ErrorDetail{
+			Code:    "unsupported_media_type",
+			Message: message,
+		})
+	})
+}
+
+// hasBody reports whether the request carries a non-empty body. Bodies of
+// unknown length are peeked at and restored.
+func hasBody(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return false
+	}
+	if r.ContentLength > 0 {
+		return true
+	}
+
+	buffered := bufio.NewReader(r.Body)
+	_, err := buffered.Peek(1)
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{buffered, r.Body}
+	return err == nil
+}
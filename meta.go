@@ -1,44 +1,90 @@
 package a2t
 
-// NewMetaToolsAdded creates a meta response for dynamically discovered tools.
-func NewMetaToolsAdded(tools ...Tool) *MetaResponse {
-	return &MetaResponse{
-		Type: "tools_added",
-		Data: map[string]interface{}{
-			"tools": tools,
-		},
-	}
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Built-in meta response types.
+const (
+	MetaTypeToolsAdded   = "tools_added"
+	MetaTypeGroupRefresh = "group_refresh"
+)
+
+// MetaDecoder validates the data of a meta response and returns its decoded
+// form, which replaces the original data in the response.
+type MetaDecoder func(data interface{}) (interface{}, error)
+
+// builtinMetaDecoders decode the meta types defined by the protocol.
+var builtinMetaDecoders = map[string]MetaDecoder{
+	MetaTypeToolsAdded: func(data interface{}) (interface{}, error) {
+		var tools []Tool
+		if err := remarshal(data, &tools); err != nil {
+			return nil, err
+		}
+		return tools, nil
+	},
+	MetaTypeGroupRefresh: func(data interface{}) (interface{}, error) {
+		var groupIDs []string
+		if err := remarshal(data, &groupIDs); err != nil {
+			return nil, err
+		}
+		return groupIDs, nil
+	},
 }
 
-// NewMetaGroupRefresh creates a meta response indicating groups need refreshing.
-func NewMetaGroupRefresh(groupIDs ...string) *MetaResponse {
-	return &MetaResponse{
-		Type: "group_refresh",
-		Data: map[string]interface{}{
-			"group_ids": groupIDs,
-		},
+// RegisterMetaType declares a meta response type the provider's executors
+// may emit and advertises it in the capabilities. The decoder validates the
+// meta data; a nil decoder accepts any data. Built-in types need no decoder.
+func (p *SimpleProvider) RegisterMetaType(typeName string, decoder MetaDecoder) {
+	if decoder == nil {
+		decoder = builtinMetaDecoders[typeName]
+	}
+	if p.metaTypes == nil {
+		p.metaTypes = make(map[string]MetaDecoder)
 	}
+	p.metaTypes[typeName] = decoder
+	p.capabilities.WithMeta(typeName)
 }
 
-// WithMeta adds a meta response to an ExecuteResponse.
-func (r *ExecuteResponse) WithMeta(meta *MetaResponse) *ExecuteResponse {
-	r.Meta = meta
-	return r
-}
+// decodeMeta validates the response's meta data against its registered or
+// built-in type, replacing it with the decoded value.
+func (p *SimpleProvider) decodeMeta(resp *ExecuteResponse) *ErrorDetail {
+	if resp.Meta == nil {
+		return nil
+	}
+
+	typeName, _ := resp.Meta.Type.(string)
+	decoder, ok := p.metaTypes[typeName]
+	if !ok {
+		decoder, ok = builtinMetaDecoders[typeName]
+	}
+	if !ok {
+		return &ErrorDetail{
+			Code:    "invalid_meta",
+			Message: fmt.Sprintf("Unknown meta type %q", typeName),
+		}
+	}
+	if decoder == nil {
+		return nil
+	}
 
-// NewExecuteResponse creates a new execute response with the given result.
-func NewExecuteResponse(result interface{}) *ExecuteResponse {
-	return &ExecuteResponse{
-		Result: result,
+	data, err := decoder(resp.Meta.Data)
+	if err != nil {
+		return &ErrorDetail{
+			Code:    "invalid_meta",
+			Message: fmt.Sprintf("Invalid %s meta: %v", typeName, err),
+		}
 	}
+	resp.Meta.Data = data
+	return nil
 }
 
-// NewExecuteError creates a new execute response with an error.
-func NewExecuteError(code, message string) *ExecuteResponse {
-	return &ExecuteResponse{
-		Error: &ErrorDetail{
-			Code:    code,
-			Message: message,
-		},
+// remarshal converts a decoded JSON value into out.
+func remarshal(in, out interface{}) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
 	}
+	return json.Unmarshal(b, out)
 }
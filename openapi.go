@@ -0,0 +1,267 @@
+package a2t
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// openAPIMethods lists the operation keys of an OpenAPI path item in a
+// stable order.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// ToolsFromOpenAPI converts each operation of an OpenAPI 3 document (JSON or
+// YAML) into a Tool. The tool name is the operationId, or a name derived from
+// the method and path when none is set. The input schema combines the
+// operation's parameters and its JSON request body; required parameters and
+// body fields are marked required.
+//
+// Executors are not generated; register one per tool by name.
+func ToolsFromOpenAPI(spec []byte) ([]*Tool, error) {
+	var s openapi3.Spec
+	if err := s.UnmarshalYAML(spec); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI spec: %w", err)
+	}
+
+	// Work on a generic JSON view of the validated spec
+	normalized, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("normalizing OpenAPI spec: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(normalized, &doc); err != nil {
+		return nil, fmt.Errorf("normalizing OpenAPI spec: %w", err)
+	}
+
+	r := &refResolver{doc: doc}
+	paths, _ := doc["paths"].(map[string]interface{})
+
+	pathNames := make([]string, 0, len(paths))
+	for path := range paths {
+		pathNames = append(pathNames, path)
+	}
+	sort.Strings(pathNames)
+
+	var tools []*Tool
+	seen := make(map[string]bool)
+	for _, path := range pathNames {
+		item, _ := r.resolve(paths[path], nil).(map[string]interface{})
+		if item == nil {
+			continue
+		}
+		shared, _ := item["parameters"].([]interface{})
+
+		for _, method := range openAPIMethods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			tool, err := r.operationTool(method, path, op, shared)
+			if err != nil {
+				return nil, err
+			}
+			if seen[tool.Name] {
+				return nil, fmt.Errorf("duplicate tool name %q from %s %s", tool.Name, strings.ToUpper(method), path)
+			}
+			seen[tool.Name] = true
+			tools = append(tools, tool)
+		}
+	}
+	return tools, nil
+}
+
+// refResolver resolves local $ref pointers within an OpenAPI document.
+type refResolver struct {
+	doc map[string]interface{}
+}
+
+// operationTool builds a Tool from a single operation.
+func (r *refResolver) operationTool(method, path string, op map[string]interface{}, shared []interface{}) (*Tool, error) {
+	name, _ := op["operationId"].(string)
+	if name == "" {
+		name = operationName(method, path)
+	}
+
+	description, _ := op["description"].(string)
+	if description == "" {
+		description, _ = op["summary"].(string)
+	}
+
+	tool := NewTool(name, description)
+	props := tool.InputSchema["properties"].(map[string]interface{})
+	var required []string
+
+	// Operation-level parameters override path-level ones with the same name and location
+	params := make(map[string]map[string]interface{})
+	var order []string
+	for _, raw := range append(append([]interface{}{}, shared...), opParams(op)...) {
+		param, _ := r.resolve(raw, nil).(map[string]interface{})
+		if param == nil {
+			continue
+		}
+		pname, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		key := in + ":" + pname
+		if _, ok := params[key]; !ok {
+			order = append(order, key)
+		}
+		params[key] = param
+	}
+
+	for _, key := range order {
+		param := params[key]
+		pname, _ := param["name"].(string)
+		if pname == "" {
+			continue
+		}
+
+		schema, _ := r.resolve(param["schema"], nil).(map[string]interface{})
+		if schema == nil {
+			schema = map[string]interface{}{"type": "string"}
+		}
+		if desc, ok := param["description"].(string); ok && desc != "" {
+			if _, has := schema["description"]; !has {
+				schema["description"] = desc
+			}
+		}
+		props[pname] = schema
+
+		if req, _ := param["required"].(bool); req || param["in"] == "path" {
+			required = append(required, pname)
+		}
+	}
+
+	if body, _ := r.resolve(op["requestBody"], nil).(map[string]interface{}); body != nil {
+		bodyRequired, _ := body["required"].(bool)
+		schema := r.jsonBodySchema(body)
+		if schema != nil {
+			bodyProps, isObject := schema["properties"].(map[string]interface{})
+			if isObject && !overlaps(props, bodyProps) {
+				for pname, prop := range bodyProps {
+					props[pname] = prop
+				}
+				if bodyRequired {
+					fields, _ := stringList(schema["required"])
+					required = append(required, fields...)
+				}
+			} else {
+				props["body"] = schema
+				if bodyRequired {
+					required = append(required, "body")
+				}
+			}
+		}
+	}
+
+	if required == nil {
+		required = []string{}
+	}
+	tool.InputSchema["required"] = required
+
+	if err := tool.Validate(); err != nil {
+		return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+	}
+	return tool, nil
+}
+
+// jsonBodySchema returns the schema of a request body's JSON content.
+func (r *refResolver) jsonBodySchema(body map[string]interface{}) map[string]interface{} {
+	content, _ := body["content"].(map[string]interface{})
+
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+
+	for _, mediaType := range mediaTypes {
+		if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+			continue
+		}
+		media, _ := content[mediaType].(map[string]interface{})
+		schema, _ := r.resolve(media["schema"], nil).(map[string]interface{})
+		return schema
+	}
+	return nil
+}
+
+// resolve returns a deep copy of v with every local $ref replaced by its
+// target. A $ref that is already being expanded (a recursive schema) is
+// replaced by an empty schema so resolution terminates.
+func (r *refResolver) resolve(v interface{}, stack []string) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := val["$ref"].(string); ok {
+			for _, active := range stack {
+				if active == ref {
+					return map[string]interface{}{}
+				}
+			}
+			return r.resolve(r.lookup(ref), append(stack, ref))
+		}
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = r.resolve(item, stack)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = r.resolve(item, stack)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// lookup follows a local JSON pointer such as "#/components/schemas/Pet".
+func (r *refResolver) lookup(ref string) interface{} {
+	if !strings.HasPrefix(ref, "#/") {
+		return map[string]interface{}{}
+	}
+
+	var cur interface{} = r.doc
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return map[string]interface{}{}
+		}
+		cur = m[part]
+	}
+	if cur == nil {
+		return map[string]interface{}{}
+	}
+	return cur
+}
+
+// opParams returns the operation's own parameter list.
+func opParams(op map[string]interface{}) []interface{} {
+	params, _ := op["parameters"].([]interface{})
+	return params
+}
+
+// overlaps reports whether any key exists in both maps.
+func overlaps(a, b map[string]interface{}) bool {
+	for k := range b {
+		if _, ok := a[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+var nonNameChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// operationName derives a tool name such as "get_users_id" from an operation
+// without an operationId.
+func operationName(method, path string) string {
+	name := nonNameChars.ReplaceAllString(path, "_")
+	return method + "_" + strings.Trim(name, "_")
+}
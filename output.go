@@ -0,0 +1,46 @@
+package a2t
+
+import (
+	"context"
+)
+
+// WithOutputSchema sets the JSON Schema describing the tool's result.
+func (t *Tool) WithOutputSchema(schema map[string]interface{}) *Tool {
+	t.OutputSchema = schema
+	return t
+}
+
+// WithValidateOutput checks tool results against their output schema,
+// replacing mismatches with an internal_error. Meant for development, as it
+// costs a re-encoding of every result.
+func (c *Capabilities) WithValidateOutput() *Capabilities {
+	c.Features.ValidateOutput = true
+	return c
+}
+
+// checkOutput validates a successful result against the tool's output schema
+// when output validation is on, logging and replacing mismatches.
+func (p *SimpleProvider) checkOutput(ctx context.Context, tool *Tool, resp *ExecuteResponse) *ExecuteResponse {
+	if !p.capabilitiesFor(ctx).Features.ValidateOutput || tool.OutputSchema == nil || resp.Error != nil || resp.Content != nil {
+		return resp
+	}
+
+	var result interface{}
+	err := remarshal(resp.Result, &result)
+	if err == nil {
+		err = schemaValidatorFromContext(ctx).Validate(tool.OutputSchema, result)
+	}
+	if err == nil {
+		return resp
+	}
+
+	p.logger.ErrorContext(ctx, "tool result does not match output schema",
+		"tool", tool.Name,
+		"error", err,
+		"request_id", RequestIDFromContext(ctx),
+	)
+	return &ExecuteResponse{Error: &ErrorDetail{
+		Code:    "internal_error",
+		Message: "Internal error while executing " + tool.Name,
+	}}
+}
@@ -0,0 +1,233 @@
+package a2t
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
+)
+
+// Result buffer defaults used when LimitsConfig leaves them unset.
+const (
+	DefaultResultBufferTTL    = 5 * time.Minute
+	DefaultMaxBufferedResults = 100
+	DefaultResultPageSize     = 100
+)
+
+// PagedResult is a large result served in pages. An executor returns it in
+// place of the full result; the response holds the first page, and the rest
+// is kept in a short-lived buffer the client reads with the page cursor.
+type PagedResult struct {
+	Items []interface{}
+
+	// PageSize is the number of items per page, DefaultResultPageSize when
+	// zero.
+	PageSize int
+}
+
+// ResultPage is one page of a PagedResult. Cursor fetches the next page
+// from GET /tools/{name}/results/{cursor} while HasMore is true.
+type ResultPage struct {
+	Items   []interface{} `json:"items"`
+	Total   int           `json:"total"`
+	Cursor  string        `json:"cursor,omitempty"`
+	HasMore bool          `json:"has_more"`
+}
+
+// GetResultPageInput represents input for reading a page of a buffered result.
+type GetResultPageInput struct {
+	Name   string `path:"name" description:"Tool name"`
+	Cursor string `path:"cursor" description:"Cursor from the previous page"`
+}
+
+// GetGroupResultPageInput represents input for reading a page of a buffered
+// group tool result.
+type GetGroupResultPageInput struct {
+	ID     string `path:"id" description:"Group ID"`
+	Name   string `path:"name" description:"Tool name"`
+	Cursor string `path:"cursor" description:"Cursor from the previous page"`
+}
+
+// bufferedResult holds the items of a paged result between page reads.
+type bufferedResult struct {
+	groupID  string
+	tool     string
+	caller   string
+	items    []interface{}
+	pageSize int
+	expires  time.Time
+}
+
+// resultBuffer keeps paged results for a limited time, evicting the oldest
+// when full.
+type resultBuffer struct {
+	ttl     time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	results map[string]*bufferedResult
+	order   []string
+}
+
+// newResultBuffer returns a buffer for the configured limits.
+func newResultBuffer(limits *LimitsConfig) *resultBuffer {
+	b := &resultBuffer{
+		ttl:     DefaultResultBufferTTL,
+		maxSize: DefaultMaxBufferedResults,
+		results: make(map[string]*bufferedResult),
+	}
+	if limits != nil && limits.ResultBufferTTL > 0 {
+		b.ttl = limits.ResultBufferTTL
+	}
+	if limits != nil && limits.MaxBufferedResults > 0 {
+		b.maxSize = limits.MaxBufferedResults
+	}
+	return b
+}
+
+// firstPage returns the first page of a paged result, buffering the rest
+// for the caller in ctx.
+func (b *resultBuffer) firstPage(ctx context.Context, groupID, toolName string, paged *PagedResult) *ResultPage {
+	result := &bufferedResult{
+		groupID:  groupID,
+		tool:     toolName,
+		caller:   callerOf(ctx),
+		items:    paged.Items,
+		pageSize: paged.PageSize,
+	}
+	if result.pageSize <= 0 {
+		result.pageSize = DefaultResultPageSize
+	}
+
+	var id string
+	if len(result.items) > result.pageSize {
+		id = b.add(result)
+	}
+	return result.page(id, 0)
+}
+
+// add stores a result and returns its ID.
+func (b *resultBuffer) add(result *bufferedResult) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.evict(now)
+	for len(b.order) >= b.maxSize {
+		delete(b.results, b.order[0])
+		b.order = b.order[1:]
+	}
+
+	id := newRequestID()
+	result.expires = now.Add(b.ttl)
+	b.results[id] = result
+	b.order = append(b.order, id)
+	return id
+}
+
+// evict drops expired results. The caller must hold b.mu.
+func (b *resultBuffer) evict(now time.Time) {
+	kept := b.order[:0]
+	for _, id := range b.order {
+		if now.After(b.results[id].expires) {
+			delete(b.results, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	b.order = kept
+}
+
+// page returns the page at a cursor, or a result_not_found error when the
+// cursor is unknown, expired or belongs to another tool or caller.
+func (b *resultBuffer) page(ctx context.Context, groupID, toolName, cursor string) (*ResultPage, error) {
+	notFound := &ErrorDetail{
+		Code:    "result_not_found",
+		Message: "Result page not found or expired: " + cursor,
+		Status:  http.StatusNotFound,
+	}
+
+	id, rawOffset, ok := strings.Cut(cursor, ".")
+	offset, err := strconv.Atoi(rawOffset)
+	if !ok || err != nil || offset < 0 {
+		return nil, notFound
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.evict(time.Now())
+	result, ok := b.results[id]
+	if !ok || result.groupID != groupID || result.tool != toolName || result.caller != callerOf(ctx) || offset >= len(result.items) {
+		return nil, notFound
+	}
+	return result.page(id, offset), nil
+}
+
+// page slices the items at offset, with a cursor for the next page.
+func (r *bufferedResult) page(id string, offset int) *ResultPage {
+	items, total := paginate(r.items, offset, r.pageSize)
+	hasMore, next := pageCursor(offset, len(items), total)
+	page := &ResultPage{Items: items, Total: total, HasMore: hasMore}
+	if hasMore {
+		page.Cursor = id + "." + strconv.Itoa(next)
+	}
+	return page
+}
+
+// callerOf identifies the authenticated caller, if any.
+func callerOf(ctx context.Context) string {
+	if claims := ClaimsFromContext(ctx); claims != nil {
+		return claims.Subject
+	}
+	return ""
+}
+
+// resultPage authorizes the tool and reads a page of its buffered result.
+func (s *Server) resultPage(ctx context.Context, groupID, toolName, cursor string, output *ExecuteResponse) error {
+	if err := s.authorize(ctx, toolName); err != nil {
+		return err
+	}
+
+	page, err := s.results.page(ctx, groupID, toolName, cursor)
+	if err != nil {
+		return err
+	}
+	output.Result = page
+	return nil
+}
+
+// resultPageUsecase reads the next page of a buffered tool result.
+func (s *Server) resultPageUsecase() usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, in GetResultPageInput, output *ExecuteResponse) error {
+		return s.resultPage(ctx, "", in.Name, in.Cursor, output)
+	})
+
+	u.SetName("getResultPage")
+	u.SetTags("Tools")
+	u.SetTitle("Get Result Page")
+	u.SetDescription("Returns the next page of a large tool result, using the cursor of the previous page")
+	u.SetExpectedErrors(status.NotFound)
+
+	return u
+}
+
+// groupResultPageUsecase reads the next page of a buffered group tool result.
+func (s *Server) groupResultPageUsecase() usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, in GetGroupResultPageInput, output *ExecuteResponse) error {
+		return s.resultPage(WithGroupID(ctx, in.ID), in.ID, in.Name, in.Cursor, output)
+	})
+
+	u.SetName("getGroupResultPage")
+	u.SetTags("Groups")
+	u.SetTitle("Get Group Tool Result Page")
+	u.SetDescription("Returns the next page of a large group tool result, using the cursor of the previous page")
+	u.SetExpectedErrors(status.NotFound)
+
+	return u
+}
@@ -0,0 +1,317 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// coerceValues converts form or query string values into tool parameters,
+// using the property types declared in the input schema. Aliases take the
+// type of their canonical property. Values for unknown properties are kept
+// as strings.
+func coerceValues(schema map[string]interface{}, aliases map[string]string, values url.Values) (map[string]interface{}, error) {
+	props, _ := schema["properties"].(map[string]interface{})
+
+	params := make(map[string]interface{}, len(values))
+	for name, raw := range values {
+		if len(raw) == 0 {
+			continue
+		}
+
+		prop, ok := props[name].(map[string]interface{})
+		if !ok {
+			prop, _ = props[aliases[name]].(map[string]interface{})
+		}
+		value, err := coerceProperty(prop, raw)
+		if err != nil {
+			return nil, &ErrorDetail{
+				Code:    "invalid_params",
+				Message: fmt.Sprintf("Parameter %q: %s", name, err.Error()),
+			}
+		}
+		params[name] = value
+	}
+	return params, nil
+}
+
+// coerceProperty converts the string values of one parameter according to
+// its property schema.
+func coerceProperty(prop map[string]interface{}, raw []string) (interface{}, error) {
+	typ, _ := prop["type"].(string)
+
+	switch typ {
+	case "array":
+		items, _ := prop["items"].(map[string]interface{})
+		out := make([]interface{}, 0, len(raw))
+		for _, s := range raw {
+			v, err := coerceString(items, s)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case "":
+		if len(raw) > 1 {
+			out := make([]interface{}, len(raw))
+			for i, s := range raw {
+				out[i] = s
+			}
+			return out, nil
+		}
+		return raw[0], nil
+	default:
+		return coerceString(prop, raw[0])
+	}
+}
+
+// coerceString converts a single string to the type declared by prop.
+// Numbers are returned as float64 to match JSON decoding.
+func coerceString(prop map[string]interface{}, s string) (interface{}, error) {
+	typ, _ := prop["type"].(string)
+
+	switch typ {
+	case "number", "integer":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected %s, got %q", typ, s)
+		}
+		if typ == "integer" && f != float64(int64(f)) {
+			return nil, fmt.Errorf("expected integer, got %q", s)
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("expected boolean, got %q", s)
+		}
+		return b, nil
+	case "object", "array":
+		var v interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("expected JSON %s, got %q", typ, s)
+		}
+		return v, nil
+	default:
+		return s, nil
+	}
+}
+
+// fieldValidator is a custom check on one parameter.
+type fieldValidator struct {
+	name string
+	fn   func(value interface{}) error
+}
+
+// WithValidator adds a custom check for the named parameter, for semantic
+// rules a schema can't express such as a valid time zone. Validators run in
+// the order added, after the schema checks pass, and only for parameters
+// that are present. An error rejects the call with invalid_params carrying
+// its message.
+func (t *Tool) WithValidator(name string, fn func(value interface{}) error) *Tool {
+	t.validators = append(t.validators, fieldValidator{name: name, fn: fn})
+	return t
+}
+
+// fieldPreprocessor rewrites the value of one parameter.
+type fieldPreprocessor struct {
+	name string
+	fn   func(value interface{}) interface{}
+}
+
+// WithPreprocessor adds a rewrite for the named parameter, for input hygiene
+// such as TrimSpace or ToLower. Preprocessors run in the order added, before
+// defaults are applied and params are validated, and only for parameters
+// that are present.
+func (t *Tool) WithPreprocessor(name string, fn func(value interface{}) interface{}) *Tool {
+	t.preprocessors = append(t.preprocessors, fieldPreprocessor{name: name, fn: fn})
+	return t
+}
+
+// TrimSpace is a preprocessor that trims surrounding whitespace from string
+// values. Other values are returned unchanged.
+func TrimSpace(value interface{}) interface{} {
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s)
+	}
+	return value
+}
+
+// ToLower is a preprocessor that lowercases string values. Other values are
+// returned unchanged.
+func ToLower(value interface{}) interface{} {
+	if s, ok := value.(string); ok {
+		return strings.ToLower(s)
+	}
+	return value
+}
+
+// WithParamAlias accepts alias as another name for the canonical parameter,
+// so renaming a parameter doesn't break existing callers. Aliased keys are
+// renamed before preprocessors, defaults and validation run; a call giving
+// both names with different values fails with invalid_params.
+func (t *Tool) WithParamAlias(canonical, alias string) *Tool {
+	if t.aliases == nil {
+		t.aliases = make(map[string]string)
+	}
+	t.aliases[alias] = canonical
+	return t
+}
+
+// resolveAliases returns params with aliased keys renamed to their
+// canonical names. The caller's map is left untouched.
+func resolveAliases(tool *Tool, params map[string]interface{}) (map[string]interface{}, *ErrorDetail) {
+	aliases := make([]string, 0, len(tool.aliases))
+	for alias := range tool.aliases {
+		if _, ok := params[alias]; ok {
+			aliases = append(aliases, alias)
+		}
+	}
+	if len(aliases) == 0 {
+		return params, nil
+	}
+	sort.Strings(aliases)
+
+	renamed := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		renamed[k] = v
+	}
+	for _, alias := range aliases {
+		canonical, value := tool.aliases[alias], renamed[alias]
+		if existing, ok := renamed[canonical]; ok && !equalValues(existing, value) {
+			return nil, invalidParams(tool, fmt.Sprintf("%s and its alias %s have different values", canonical, alias))
+		}
+		delete(renamed, alias)
+		renamed[canonical] = value
+	}
+	return renamed, nil
+}
+
+// ParamInjector returns params to inject into a call from its context.
+type ParamInjector func(ctx context.Context) map[string]interface{}
+
+// mergeParams returns params with extra merged in, replacing the caller's
+// values when override is set and filling omitted params otherwise. The
+// caller's map is left untouched.
+func mergeParams(params, extra map[string]interface{}, override bool) map[string]interface{} {
+	if len(extra) == 0 {
+		return params
+	}
+
+	merged := make(map[string]interface{}, len(params)+len(extra))
+	for k, v := range params {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		if _, ok := merged[k]; ok && !override {
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// preprocess returns params rewritten by the tool's preprocessors. The
+// caller's map is left untouched.
+func preprocess(tool *Tool, params map[string]interface{}) map[string]interface{} {
+	var rewritten map[string]interface{}
+	for _, pre := range tool.preprocessors {
+		value, ok := params[pre.name]
+		if !ok {
+			continue
+		}
+		if rewritten == nil {
+			rewritten = make(map[string]interface{}, len(params))
+			for k, v := range params {
+				rewritten[k] = v
+			}
+			params = rewritten
+		}
+		params[pre.name] = pre.fn(value)
+	}
+	return params
+}
+
+// WithValidateParams checks execution parameters against the tool's input
+// schema, rejecting mismatches with invalid_params.
+func (c *Capabilities) WithValidateParams() *Capabilities {
+	c.Features.ValidateParams = true
+	return c
+}
+
+// applyDefaults returns params with declared defaults filled in for omitted
+// top-level properties. The caller's map is left untouched.
+func applyDefaults(tool *Tool, params map[string]interface{}) map[string]interface{} {
+	props, _ := tool.InputSchema["properties"].(map[string]interface{})
+
+	var filled map[string]interface{}
+	for name, raw := range props {
+		prop, _ := raw.(map[string]interface{})
+		def, ok := prop["default"]
+		if !ok {
+			continue
+		}
+		if _, present := params[name]; present {
+			continue
+		}
+		if filled == nil {
+			filled = make(map[string]interface{}, len(params)+1)
+			for k, v := range params {
+				filled[k] = v
+			}
+		}
+		filled[name] = def
+	}
+
+	if filled == nil {
+		return params
+	}
+	return filled
+}
+
+// checkParams validates parameters against the tool's input schema with
+// the validator in ctx when parameter validation is on, then runs the tool's
+// custom validators. Unknown params of tools that disallow them are
+// rejected either way.
+func (p *SimpleProvider) checkParams(ctx context.Context, tool *Tool, params map[string]interface{}) *ErrorDetail {
+	if p.capabilitiesFor(ctx).Features.ValidateParams {
+		var values interface{} = map[string]interface{}{}
+		if params != nil {
+			if err := remarshal(params, &values); err != nil {
+				return &ErrorDetail{Code: "invalid_params", Message: err.Error(), Status: http.StatusBadRequest}
+			}
+		}
+		if err := schemaValidatorFromContext(ctx).Validate(tool.InputSchema, values); err != nil {
+			return invalidParams(tool, err.Error())
+		}
+	} else if err := checkAdditionalProperties(tool.InputSchema, params, ""); err != nil {
+		// Strict tools reject unknown params even without schema validation
+		return invalidParams(tool, err.Error())
+	}
+
+	for _, v := range tool.validators {
+		value, ok := params[v.name]
+		if !ok {
+			continue
+		}
+		if err := v.fn(value); err != nil {
+			return invalidParams(tool, v.name+": "+err.Error())
+		}
+	}
+	return nil
+}
+
+// invalidParams reports parameters that failed validation.
+func invalidParams(tool *Tool, message string) *ErrorDetail {
+	return &ErrorDetail{
+		Code:    "invalid_params",
+		Message: "Invalid params for " + tool.Name + ": " + message,
+		Status:  http.StatusBadRequest,
+	}
+}
@@ -0,0 +1,157 @@
+package a2t
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PipelineStep is one tool call of a pipeline.
+//
+// Inputs maps a param of the step to a reference to an earlier value:
+// "input" is the pipeline's params and "prev" the previous step's result,
+// "steps.N" the result of step N (counting from 0). Any reference can be
+// followed by a dotted path into objects and arrays, as in
+// "input.url" or "steps.0.items.2". Values are passed in their JSON form,
+// as they would be over HTTP. Inputs take precedence over Params; an input
+// whose field is absent is left unset.
+type PipelineStep struct {
+	Tool    string
+	GroupID string
+
+	// Params are fixed params passed to the step.
+	Params map[string]interface{}
+	Inputs map[string]string
+}
+
+// RegisterPipeline registers a tool that runs the steps in order, threading
+// each step's result into the next, and returns the last step's result.
+// Steps are executed through the provider like any call, so their hooks,
+// defaults and validation apply. The first failing step stops the pipeline
+// and its error is returned, with the step prefixed to the message.
+//
+// The returned tool can be described further with the Tool builders. It
+// panics when there are no steps.
+func (p *SimpleProvider) RegisterPipeline(name string, steps []PipelineStep) *Tool {
+	if len(steps) == 0 {
+		panic(fmt.Sprintf("a2t: pipeline %s: no steps", name))
+	}
+	names := make([]string, len(steps))
+	for i, step := range steps {
+		names[i] = step.Tool
+	}
+	tool := NewTool(name, "Pipeline: "+strings.Join(names, " → "))
+
+	p.RegisterTool(tool, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return p.runPipeline(ctx, steps, params), nil
+	})
+	return tool
+}
+
+// runPipeline executes the steps, stopping at the first error.
+func (p *SimpleProvider) runPipeline(ctx context.Context, steps []PipelineStep, input map[string]interface{}) *ExecuteResponse {
+	results := make([]interface{}, 0, len(steps))
+	resp := &ExecuteResponse{}
+
+	for i, step := range steps {
+		params := make(map[string]interface{}, len(step.Params)+len(step.Inputs))
+		for k, v := range step.Params {
+			params[k] = v
+		}
+		for param, ref := range step.Inputs {
+			value, ok, err := resolveReference(ref, input, results)
+			if err != nil {
+				return &ExecuteResponse{Error: &ErrorDetail{
+					Code:    "invalid_pipeline",
+					Message: fmt.Sprintf("step %d (%s): input %s: %v", i, step.Tool, param, err),
+				}}
+			}
+			if ok {
+				params[param] = value
+			}
+		}
+
+		var err error
+		resp, err = p.ExecuteTool(WithGroupID(ctx, step.GroupID), step.Tool, params)
+		if err != nil {
+			return &ExecuteResponse{Error: &ErrorDetail{
+				Code:    "execution_error",
+				Message: fmt.Sprintf("step %d (%s): %v", i, step.Tool, err),
+			}}
+		}
+		if resp.Error != nil {
+			detail := *resp.Error
+			detail.Message = fmt.Sprintf("step %d (%s): %s", i, step.Tool, detail.Message)
+			return &ExecuteResponse{Error: &detail}
+		}
+		results = append(results, resp.Result)
+	}
+	return resp
+}
+
+// resolveReference looks up a pipeline input reference. It reports false
+// for a field absent from an object, so the param is left unset.
+func resolveReference(ref string, input map[string]interface{}, results []interface{}) (interface{}, bool, error) {
+	parts := strings.Split(ref, ".")
+
+	var value interface{}
+	switch parts[0] {
+	case "input":
+		value, parts = input, parts[1:]
+	case "prev":
+		if len(results) == 0 {
+			return nil, false, fmt.Errorf("no previous step")
+		}
+		value, parts = results[len(results)-1], parts[1:]
+	case "steps":
+		if len(parts) < 2 {
+			return nil, false, fmt.Errorf("reference %q names no step", ref)
+		}
+		n, err := strconv.Atoi(parts[1])
+		if err != nil || n < 0 || n >= len(results) {
+			return nil, false, fmt.Errorf("reference %q names no earlier step", ref)
+		}
+		value, parts = results[n], parts[2:]
+	default:
+		return nil, false, fmt.Errorf("reference %q must start with input, prev or steps", ref)
+	}
+
+	value, ok, err := lookupPath(value, parts)
+	if err != nil {
+		return nil, false, fmt.Errorf("reference %q: %v", ref, err)
+	}
+	return value, ok, nil
+}
+
+// lookupPath follows path segments through the JSON form of value, indexing
+// objects by key and arrays by position. It reports false for a key absent
+// from an object.
+func lookupPath(value interface{}, parts []string) (interface{}, bool, error) {
+	// Walk results such as structs in their JSON form, which is also the
+	// form steps get their params in over HTTP
+	var generic interface{}
+	if err := remarshal(value, &generic); err != nil {
+		return nil, false, err
+	}
+	value = generic
+	for _, part := range parts {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			field, ok := v[part]
+			if !ok {
+				return nil, false, nil
+			}
+			value = field
+		case []interface{}:
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 || n >= len(v) {
+				return nil, false, fmt.Errorf("no element %s", part)
+			}
+			value = v[n]
+		default:
+			return nil, false, fmt.Errorf("%s is not an object or array", part)
+		}
+	}
+	return value, true, nil
+}
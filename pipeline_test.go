@@ -0,0 +1,123 @@
+package a2t
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// newPipelineProvider registers "fetch", which returns a document for its
+// url, "count", which counts the words of its text, and "math.double".
+func newPipelineProvider() *GroupProviderImpl {
+	p := NewGroupProvider(NewCapabilities().WithGroups(""))
+	p.RegisterGroup(NewGroup("math", "Math", "Math tools"))
+	p.RegisterTool(NewTool("fetch", "Fetch a document"), func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		url, _ := params["url"].(string)
+		if url == "" {
+			return nil, errors.New("url is required")
+		}
+		return map[string]interface{}{"url": url, "body": "one two three", "tags": []string{"a", "b"}}, nil
+	})
+	p.RegisterTool(NewTool("count", "Count words"), func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		text, _ := params["text"].(string)
+		return len(strings.Fields(text)), nil
+	})
+	p.RegisterTool(NewTool("double", "Double a number").WithGroup("math"), func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		n, _ := params["n"].(float64)
+		return n * 2, nil
+	})
+	return p
+}
+
+func TestPipelineThreadsResults(t *testing.T) {
+	p := newPipelineProvider()
+	p.RegisterPipeline("word_count", []PipelineStep{
+		{Tool: "fetch", Inputs: map[string]string{"url": "input.url"}},
+		{Tool: "count", Inputs: map[string]string{"text": "prev.body"}},
+		{Tool: "double", GroupID: "math", Inputs: map[string]string{"n": "prev"}},
+	})
+
+	resp, err := p.ExecuteTool(context.Background(), "word_count", map[string]interface{}{"url": "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != nil || resp.Result != 6.0 {
+		t.Errorf("got result %v, error %v; want 6", resp.Result, resp.Error)
+	}
+}
+
+func TestPipelineStopsAtFirstFailure(t *testing.T) {
+	p := newPipelineProvider()
+	ran := false
+	p.RegisterTool(NewTool("after", "Runs after fetch"), func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		ran = true
+		return nil, nil
+	})
+	p.RegisterPipeline("broken", []PipelineStep{
+		{Tool: "fetch", Inputs: map[string]string{"url": "input.missing"}},
+		{Tool: "after"},
+	})
+
+	resp, err := p.ExecuteTool(context.Background(), "broken", map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil || !strings.HasPrefix(resp.Error.Message, "step 0 (fetch): ") {
+		t.Errorf("got error %v, want one prefixed with step 0 (fetch)", resp.Error)
+	}
+	if ran {
+		t.Error("the step after the failure ran")
+	}
+}
+
+func TestPipelineReferences(t *testing.T) {
+	tests := []struct {
+		ref   string
+		valid bool
+	}{
+		{"steps.0.tags.1", true},
+		{"steps.0.absent", true},
+		{"steps.1", false},
+		{"steps.-1", false},
+		{"steps.x", false},
+		{"steps", false},
+		{"steps.0.tags.9", false},
+		{"steps.0.url.deeper", false},
+		{"output.url", false},
+	}
+	for _, tt := range tests {
+		p := newPipelineProvider()
+		p.RegisterPipeline("pipe", []PipelineStep{
+			{Tool: "fetch", Params: map[string]interface{}{"url": "https://example.com"}},
+			{Tool: "count", Inputs: map[string]string{"text": tt.ref}},
+		})
+
+		resp, err := p.ExecuteTool(context.Background(), "pipe", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.valid && resp.Error != nil {
+			t.Errorf("%s: unexpected error %v", tt.ref, resp.Error)
+		}
+		if !tt.valid && (resp.Error == nil || resp.Error.Code != "invalid_pipeline") {
+			t.Errorf("%s: got error %v, want invalid_pipeline", tt.ref, resp.Error)
+		}
+	}
+
+	p := newPipelineProvider()
+	p.RegisterPipeline("first", []PipelineStep{{Tool: "count", Inputs: map[string]string{"text": "prev"}}})
+	resp, _ := p.ExecuteTool(context.Background(), "first", nil)
+	if resp.Error == nil || resp.Error.Code != "invalid_pipeline" {
+		t.Errorf("prev in the first step: got error %v, want invalid_pipeline", resp.Error)
+	}
+}
+
+func TestPipelineWithoutStepsPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterPipeline with no steps did not panic")
+		}
+	}()
+	NewSimpleProvider(NewCapabilities()).RegisterPipeline("empty", nil)
+}
@@ -0,0 +1,122 @@
+package a2t
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 9457 problem documents.
+const ProblemContentType = "application/problem+json"
+
+// DefaultProblemTypeBase prefixes error codes to form problem type URIs,
+// as in urn:a2t:error:tool_not_found.
+const DefaultProblemTypeBase = "urn:a2t:error:"
+
+type problemTypeBaseKey struct{}
+
+// Problem is an RFC 9457 problem details document. Code carries the a2t
+// error code as an extension member.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Debug    string `json:"debug,omitempty"`
+
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// WithProblemDetails renders every error response as an
+// application/problem+json document instead of the {"error": {...}} shape.
+// Without it, clients still get problem documents by sending
+// Accept: application/problem+json.
+func WithProblemDetails() ServerOption {
+	return func(s *Server) {
+		s.problemDetails = true
+	}
+}
+
+// WithProblemTypeBase sets the URI prefix joined with an error code to form
+// a problem's type, DefaultProblemTypeBase by default. Use it to point types
+// at human-readable documentation, such as "https://example.com/errors/".
+func WithProblemTypeBase(base string) ServerOption {
+	return func(s *Server) {
+		s.problemTypeBase = base
+	}
+}
+
+// negotiateProblems selects problem documents for the request's error
+// responses when they are enabled server-wide or the client accepts them.
+func (s *Server) negotiateProblems(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.problemDetails && !acceptsProblems(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), problemTypeBaseKey{}, s.problemTypeBase)
+		next.ServeHTTP(&problemWriter{ResponseWriter: w}, r.WithContext(ctx))
+	})
+}
+
+// acceptsProblems reports whether the Accept header names the problem
+// media type explicitly.
+func acceptsProblems(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(part)
+			if err == nil && mediaType == ProblemContentType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// problemFor converts an error response into a problem document when the
+// request asked for one. ok is false when it didn't.
+func problemFor(ctx context.Context, status int, detail *ErrorDetail) (problem *Problem, ok bool) {
+	base, ok := ctx.Value(problemTypeBaseKey{}).(string)
+	if !ok {
+		return nil, false
+	}
+
+	problem = &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: RequestIDFromContext(ctx),
+	}
+	if detail != nil {
+		problem.Type = base + detail.Code
+		problem.Detail = detail.Message
+		problem.Code = detail.Code
+		problem.Debug = detail.Debug
+		problem.Details = detail.Details
+	}
+	return problem, true
+}
+
+// problemWriter labels JSON error responses with the problem media type.
+type problemWriter struct {
+	http.ResponseWriter
+}
+
+func (w *problemWriter) WriteHeader(status int) {
+	if status >= http.StatusBadRequest {
+		mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		if mediaType == "application/json" {
+			w.Header().Set("Content-Type", ProblemContentType)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *problemWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
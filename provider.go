@@ -2,7 +2,16 @@ package a2t
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime/debug"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 // ToolProvider is the main interface that tool implementations must satisfy.
@@ -31,16 +40,85 @@ type GroupProvider interface {
 
 	// GetGroup returns a specific group by ID.
 	GetGroup(ctx context.Context, groupID string) (*Group, error)
+
+	// ExecuteGroupTool executes a tool that is a member of the given group.
+	// Tools that exist only outside the group are reported as
+	// tool_not_in_group, and unknown tools as tool_not_found.
+	ExecuteGroupTool(ctx context.Context, groupID, toolName string, params map[string]interface{}) (*ExecuteResponse, error)
+}
+
+// ToolGetter is an optional interface for providers that can look up a
+// single tool by name.
+type ToolGetter interface {
+	// GetTool returns the named tool, or a tool_not_found error.
+	GetTool(ctx context.Context, toolName string) (*Tool, error)
 }
 
 // ToolExecutor is a function that executes a tool.
 type ToolExecutor func(ctx context.Context, params map[string]interface{}) (interface{}, error)
 
+// BeforeHook runs before a tool executes. It may modify params; returning an
+// error aborts execution and is reported as the execution error.
+type BeforeHook func(ctx context.Context, toolName string, params map[string]interface{}) error
+
+// AfterHook runs after a tool executes and may modify the response.
+type AfterHook func(ctx context.Context, toolName string, resp *ExecuteResponse)
+
+// ProviderMiddleware wraps a tool executor. Unlike HTTP middleware it
+// applies to every invocation of the tool, over any transport or in process,
+// which suits cross-cutting retries, timeouts and metrics.
+type ProviderMiddleware func(next ToolExecutor) ToolExecutor
+
+// toolKey identifies a registered tool. The same name may be registered in
+// several groups.
+type toolKey struct {
+	groupID string
+	name    string
+}
+
+// registeredTool is a registered tool with its executors, as looked up
+// under the catalog lock.
+type registeredTool struct {
+	key      toolKey
+	tool     *Tool
+	executor ToolExecutor
+	writer   WriterExecutor
+}
+
 // SimpleProvider is a basic in-memory implementation of ToolProvider.
 type SimpleProvider struct {
 	capabilities *Capabilities
-	tools        map[string]*Tool
-	executors    map[string]ToolExecutor
+
+	// catalogMu guards tools, executors, writers and builtins. When both
+	// are needed it is taken before GroupProviderImpl.mu.
+	catalogMu    sync.RWMutex
+	tools        map[toolKey]*Tool
+	executors    map[toolKey]ToolExecutor
+	writers      map[toolKey]WriterExecutor
+	builtins     map[string]bool
+	beforeHooks  []BeforeHook
+	afterHooks   []AfterHook
+	middleware   []ProviderMiddleware
+	metaTypes    map[string]MetaDecoder
+	logger       *slog.Logger
+	debugPanics  bool
+	errorMapper  ErrorMapper
+	flights      flightGroup
+	descriptions *descriptionCache
+
+	// groupHidden reports whether a group is hidden from the caller in ctx,
+	// making its tools inaccessible. Set by GroupProviderImpl.SetGroupFilter.
+	groupHidden func(ctx context.Context, groupID string) bool
+
+	// groupParams adds a group's default and injected params to a call of
+	// one of its tools. Set by NewGroupProvider.
+	groupParams func(ctx context.Context, groupID string, params map[string]interface{}) map[string]interface{}
+
+	// namespace registers the group a tool with a dotted name is filed
+	// under, and its ancestors, if missing. Set by
+	// GroupProviderImpl.WithNamespaceRouting. It is called with
+	// p.catalogMu held.
+	namespace func(groupID string)
 }
 
 // NewSimpleProvider creates a new simple provider.
@@ -50,15 +128,196 @@ func NewSimpleProvider(capabilities *Capabilities) *SimpleProvider {
 	}
 	return &SimpleProvider{
 		capabilities: capabilities,
-		tools:        make(map[string]*Tool),
-		executors:    make(map[string]ToolExecutor),
+		tools:        make(map[toolKey]*Tool),
+		executors:    make(map[toolKey]ToolExecutor),
+		writers:      make(map[toolKey]WriterExecutor),
+		logger:       slog.Default(),
 	}
 }
 
-// RegisterTool registers a tool with its executor function.
+// RegisterTool registers a tool with its executor function. Tools are keyed
+// by group and name, so the same name can be registered in several groups.
+// Re-registering a tool replaces it, keeping the original creation time,
+// and logs a warning; use RegisterToolStrict to reject duplicates instead.
+// It panics on a tool with Errors or an invalid ResultTemplate, which
+// RegisterToolChecked returns as an error instead, and on an ungrouped tool
+// with a reserved name.
 func (p *SimpleProvider) RegisterTool(tool *Tool, executor ToolExecutor) {
-	p.tools[tool.Name] = tool
-	p.executors[tool.Name] = executor
+	p.mustRegister(tool, executor, nil)
+}
+
+// mustRegister registers or replaces a tool, panicking if it can't be
+// registered.
+func (p *SimpleProvider) mustRegister(tool *Tool, executor ToolExecutor, writer WriterExecutor) {
+	if errDetail := p.register(tool, executor, writer, true); errDetail != nil {
+		panic("a2t: " + errDetail.Message)
+	}
+}
+
+// reservedToolNames are taken by fixed routes under the tools endpoint,
+// GET /tools/export and POST /tools/batch, so ungrouped tools can't use them.
+var reservedToolNames = map[string]bool{
+	"export": true,
+	"batch":  true,
+}
+
+// register stores a tool with its executor and, for writer tools, its
+// writer. Unless replace is set, a tool already registered under the same
+// group and name is kept and a duplicate_tool error returned. Ungrouped
+// tools with reserved names are rejected with reserved_tool_name.
+func (p *SimpleProvider) register(tool *Tool, executor ToolExecutor, writer WriterExecutor, replace bool) *ErrorDetail {
+	if len(tool.errs) > 0 {
+		panic(fmt.Sprintf("a2t: tool %s: %v", qualifiedName(tool), tool.errs[0]))
+	}
+	if err := validateResultTemplate(tool.ResultTemplate); err != nil {
+		panic(fmt.Sprintf("a2t: tool %s: %v", qualifiedName(tool), err))
+	}
+
+	// Work out where namespace routing files the tool, but leave the tool
+	// and groups alone until registration can no longer fail
+	groupID, name := tool.GroupID, tool.Name
+	if p.namespace != nil && groupID == "" {
+		if namespace, short, ok := splitNamespace(name); ok {
+			groupID, name = namespace, short
+		}
+	}
+	if groupID == "" && reservedToolNames[name] {
+		return &ErrorDetail{
+			Code:    "reserved_tool_name",
+			Message: fmt.Sprintf("Tool name %s is reserved for the %s endpoint; register it in a group", name, name),
+		}
+	}
+	key := toolKey{groupID: groupID, name: name}
+
+	p.catalogMu.Lock()
+	defer p.catalogMu.Unlock()
+
+	existing, exists := p.tools[key]
+	if exists && !replace {
+		return &ErrorDetail{
+			Code:    "duplicate_tool",
+			Message: fmt.Sprintf("Tool already registered: %s", qualifiedName(&Tool{GroupID: groupID, Name: name})),
+		}
+	}
+
+	if groupID != tool.GroupID {
+		tool.GroupID, tool.Name = groupID, name
+		p.namespace(groupID)
+	}
+	now := time.Now()
+	if exists {
+		p.logger.Warn("overwriting registered tool", "tool", tool.Name, "group_id", tool.GroupID)
+		if !existing.CreatedAt.IsZero() {
+			tool.CreatedAt = existing.CreatedAt
+		}
+	}
+	if tool.CreatedAt.IsZero() {
+		tool.CreatedAt = now
+	}
+	tool.UpdatedAt = now
+	tool.ETag = toolETag(tool)
+
+	p.tools[key] = tool
+	p.executors[key] = executor
+	if writer != nil {
+		p.writers[key] = writer
+	} else {
+		delete(p.writers, key)
+	}
+	return nil
+}
+
+// RegisterToolChecked validates the tool's input schema before registering it.
+// Invalid tools are rejected and the specific inconsistency is returned.
+func (p *SimpleProvider) RegisterToolChecked(tool *Tool, executor ToolExecutor) error {
+	if err := tool.Validate(); err != nil {
+		return err
+	}
+	if errDetail := p.register(tool, executor, nil, true); errDetail != nil {
+		return errDetail
+	}
+	return nil
+}
+
+// RegisterToolStrict registers a tool like RegisterTool, but returns a
+// duplicate_tool error instead of replacing a tool already registered under
+// the same group and name.
+func (p *SimpleProvider) RegisterToolStrict(tool *Tool, executor ToolExecutor) error {
+	if errDetail := p.register(tool, executor, nil, false); errDetail != nil {
+		return errDetail
+	}
+	return nil
+}
+
+// HasTool reports whether a tool with the name is registered, in any group.
+func (p *SimpleProvider) HasTool(name string) bool {
+	p.catalogMu.RLock()
+	defer p.catalogMu.RUnlock()
+
+	for key := range p.tools {
+		if key.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// inHiddenGroup reports whether the group is hidden from the caller in ctx.
+func (p *SimpleProvider) inHiddenGroup(ctx context.Context, groupID string) bool {
+	return groupID != "" && p.groupHidden != nil && p.groupHidden(ctx, groupID)
+}
+
+// qualifiedName returns the tool's name prefixed with its group, if any.
+func qualifiedName(tool *Tool) string {
+	if tool.GroupID == "" {
+		return tool.Name
+	}
+	return tool.GroupID + "/" + tool.Name
+}
+
+// AddBeforeHook registers a hook that runs before every tool execution.
+// Hooks run in registration order; the first error stops execution.
+func (p *SimpleProvider) AddBeforeHook(hook BeforeHook) {
+	p.beforeHooks = append(p.beforeHooks, hook)
+}
+
+// UseExecutorMiddleware adds middleware wrapping every tool executor,
+// including tools registered later. The first middleware given is the
+// outermost. Middleware runs after the before hooks; the executing tool's
+// name is available from ToolNameFromContext.
+func (p *SimpleProvider) UseExecutorMiddleware(middleware ...ProviderMiddleware) {
+	p.middleware = append(p.middleware, middleware...)
+}
+
+// AddAfterHook registers a hook that runs after every tool execution,
+// including executions that failed. Hooks run in registration order.
+func (p *SimpleProvider) AddAfterHook(hook AfterHook) {
+	p.afterHooks = append(p.afterHooks, hook)
+}
+
+// SetLogger sets the logger used to report executor panics, overwritten
+// tools and failed streams. It defaults to slog.Default().
+func (p *SimpleProvider) SetLogger(logger *slog.Logger) {
+	p.logger = logger
+}
+
+// Logger returns the logger set with SetLogger.
+func (p *SimpleProvider) Logger() *slog.Logger {
+	return p.logger
+}
+
+// SetDebugPanics controls whether the recovered panic value is returned in
+// the debug field of internal_error responses. Leave it off in production,
+// as the value may reveal internals.
+func (p *SimpleProvider) SetDebugPanics(enabled bool) {
+	p.debugPanics = enabled
+}
+
+// SetErrorMapper sets the mapper used to convert executor errors into
+// specific error codes and HTTP statuses. Tool-level mappers take precedence;
+// unmapped errors are reported as execution_error.
+func (p *SimpleProvider) SetErrorMapper(mapper ErrorMapper) {
+	p.errorMapper = mapper
 }
 
 // GetCapabilities returns the provider's capabilities.
@@ -66,79 +325,351 @@ func (p *SimpleProvider) GetCapabilities() *Capabilities {
 	return p.capabilities
 }
 
-// ListTools returns all registered tools.
-func (p *SimpleProvider) ListTools(ctx context.Context, groupID, query string, offset, limit int) (*ToolsResponse, error) {
-	var tools []Tool
+// AllTools returns copies of every registered tool, ordered by group and
+// name, without pagination, search, scope or availability filtering. It is
+// meant for server-side consumers such as exporters that need the whole
+// catalog.
+func (p *SimpleProvider) AllTools() []Tool {
+	p.catalogMu.RLock()
+	defer p.catalogMu.RUnlock()
+
+	tools := make([]Tool, 0, len(p.tools))
 	for _, tool := range p.tools {
-		// Filter by group
-		if groupID != "" && tool.GroupID != groupID {
-			continue
-		}
+		tools = append(tools, copyTool(tool))
+	}
+	sortTools(tools, "group")
+	return tools
+}
 
-		// Filter by search query
-		if query != "" {
-			if !matchesQuery(tool.Name, tool.Description, query) {
-				continue
+// GetTool returns a copy of the named tool, resolved within the group
+// recorded in ctx if any.
+func (p *SimpleProvider) GetTool(ctx context.Context, toolName string) (*Tool, error) {
+	entry, errDetail := p.lookup(ctx, toolName)
+	if errDetail != nil {
+		return nil, errDetail
+	}
+	c := copyTool(entry.tool)
+	p.describeTool(ctx, &c)
+	return &c, nil
+}
+
+// lookup resolves a tool under the catalog lock, returning it with its
+// executors so they can be used once the lock is released.
+func (p *SimpleProvider) lookup(ctx context.Context, toolName string) (registeredTool, *ErrorDetail) {
+	p.catalogMu.RLock()
+	defer p.catalogMu.RUnlock()
+
+	key, errDetail := p.resolve(ctx, toolName)
+	if errDetail != nil {
+		return registeredTool{key: key}, errDetail
+	}
+	return registeredTool{
+		key:      key,
+		tool:     p.tools[key],
+		executor: p.executors[key],
+		writer:   p.writers[key],
+	}, nil
+}
+
+// resolve finds the registered tool for a name. Within the group recorded
+// in ctx only that group's tool matches. Otherwise an ungrouped tool wins,
+// then, with namespace routing, the tool a dotted name like "math.add"
+// names, then a tool whose name is registered in a single group. A tool
+// registered only outside the requested group is reported as
+// tool_not_in_group. Tools in groups hidden from the caller are treated as
+// absent. The caller must hold p.catalogMu.
+func (p *SimpleProvider) resolve(ctx context.Context, toolName string) (toolKey, *ErrorDetail) {
+	groupID := GroupIDFromContext(ctx)
+	key := toolKey{groupID: groupID, name: toolName}
+	if _, ok := p.tools[key]; ok && !p.inHiddenGroup(ctx, key.groupID) {
+		return key, nil
+	}
+	if groupID != "" {
+		for k := range p.tools {
+			if k.name == toolName && !p.inHiddenGroup(ctx, k.groupID) {
+				return key, &ErrorDetail{
+					Code:    "tool_not_in_group",
+					Message: "Tool " + toolName + " is not a member of group " + groupID,
+				}
 			}
 		}
+		return key, &ErrorDetail{
+			Code:    "tool_not_found",
+			Message: "Tool not found: " + toolName,
+		}
+	}
+	if p.namespace != nil {
+		if groupID, name, ok := splitNamespace(toolName); ok {
+			k := toolKey{groupID: groupID, name: name}
+			if _, ok := p.tools[k]; ok && !p.inHiddenGroup(ctx, groupID) {
+				return k, nil
+			}
+		}
+	}
+
+	var matches []toolKey
+	for k := range p.tools {
+		if k.name == toolName && !p.inHiddenGroup(ctx, k.groupID) {
+			matches = append(matches, k)
+		}
+	}
 
-		tools = append(tools, *tool)
+	switch len(matches) {
+	case 0:
+		return key, &ErrorDetail{
+			Code:    "tool_not_found",
+			Message: "Tool not found: " + toolName,
+		}
+	case 1:
+		return matches[0], nil
+	default:
+		return key, &ErrorDetail{
+			Code:    "ambiguous_tool",
+			Message: "Tool " + toolName + " exists in several groups; execute it through its group",
+		}
 	}
+}
 
-	total := len(tools)
+// ListTools returns all registered tools. A tool belongs to exactly one
+// group, so each appears once and Total counts distinct tools; tools that
+// share a name across groups are separate tools and are listed separately.
+func (p *SimpleProvider) ListTools(ctx context.Context, groupID, query string, offset, limit int) (*ToolsResponse, error) {
+	tools := p.filterTools(ctx, groupID, query)
 
-	// Apply pagination
-	if offset >= len(tools) {
-		tools = []Tool{}
-	} else {
-		end := offset + limit
-		if limit == 0 || end > len(tools) {
-			end = len(tools)
+	sortTools(tools, ListOptionsFromContext(ctx).Sort)
+	tools, total := paginate(tools, offset, limit)
+
+	// Describe only the page, so the resolver isn't asked about every tool
+	for i := range tools {
+		p.describeTool(ctx, &tools[i])
+		if query != "" && ListOptionsFromContext(ctx).Highlight {
+			tools[i].Highlights = highlightTool(&tools[i], query, ListOptionsFromContext(ctx).SearchFields)
 		}
-		tools = tools[offset:end]
 	}
 
+	hasMore, nextOffset := pageCursor(offset, len(tools), total)
+
 	return &ToolsResponse{
-		Tools:  tools,
-		Total:  total,
-		Offset: offset,
-		Limit:  limit,
+		Tools:      tools,
+		Total:      total,
+		Offset:     offset,
+		Limit:      limit,
+		HasMore:    hasMore,
+		NextOffset: nextOffset,
 	}, nil
 }
 
-// ExecuteTool executes a registered tool.
+// filterTools returns copies of the tools in a listing, before sorting and
+// pagination.
+func (p *SimpleProvider) filterTools(ctx context.Context, groupID, query string) []Tool {
+	p.catalogMu.RLock()
+	defer p.catalogMu.RUnlock()
+
+	var tools []Tool
+	for _, tool := range p.tools {
+		if p.listed(ctx, tool, groupID, query) {
+			tools = append(tools, copyTool(tool))
+		}
+	}
+	return tools
+}
+
+// CountTools returns the number of tools an unfiltered listing would hold
+// for the caller, without copying or sorting them.
+func (p *SimpleProvider) CountTools(ctx context.Context) int {
+	p.catalogMu.RLock()
+	defer p.catalogMu.RUnlock()
+
+	count := 0
+	for _, tool := range p.tools {
+		if p.listed(ctx, tool, "", "") {
+			count++
+		}
+	}
+	return count
+}
+
+// listed reports whether a listing of the group matching the query holds
+// the tool. The caller must hold p.catalogMu.
+func (p *SimpleProvider) listed(ctx context.Context, tool *Tool, groupID, query string) bool {
+	// Filter by group
+	if groupID != "" && tool.GroupID != groupID {
+		return false
+	}
+
+	// Filter by search query
+	if query != "" {
+		if !matchesTool(tool, query, ListOptionsFromContext(ctx).SearchFields) {
+			return false
+		}
+	}
+
+	if ListOptionsFromContext(ctx).ReadOnly && !tool.ReadOnly {
+		return false
+	}
+	if p.isBuiltin(tool) && !ListOptionsFromContext(ctx).IncludeBuiltins {
+		return false
+	}
+
+	// Hide tools the caller lacks the scopes for
+	if len(missingScopes(ClaimsFromContext(ctx), tool.Scopes)) > 0 {
+		return false
+	}
+
+	// Hide tools switched off for this request
+	if !tool.isAvailable(ctx) {
+		return false
+	}
+
+	// Hide tools in groups hidden from the caller
+	return !p.inHiddenGroup(ctx, tool.GroupID)
+}
+
+// ExecuteTool executes a registered tool, resolved within the group
+// recorded in ctx if any.
 func (p *SimpleProvider) ExecuteTool(ctx context.Context, toolName string, params map[string]interface{}) (*ExecuteResponse, error) {
-	executor, ok := p.executors[toolName]
-	if !ok {
-		return &ExecuteResponse{
-			Error: &ErrorDetail{
-				Code:    "tool_not_found",
-				Message: "Tool not found: " + toolName,
-			},
-		}, nil
+	entry, params, errDetail := p.prepare(ctx, toolName, params)
+	if errDetail != nil {
+		return &ExecuteResponse{Error: errDetail}, nil
+	}
+
+	resp := p.execute(ctx, entry.tool, p.singleFlight(entry), params)
+	for _, hook := range p.afterHooks {
+		hook(ctx, toolName, resp)
+	}
+
+	if errDetail := p.decodeMeta(resp); errDetail != nil {
+		return &ExecuteResponse{Error: errDetail}, nil
+	}
+	return resp, nil
+}
+
+// prepare resolves a tool for execution, checking that it is available and
+// validating its params once aliases, group params and defaults are applied.
+func (p *SimpleProvider) prepare(ctx context.Context, toolName string, params map[string]interface{}) (registeredTool, map[string]interface{}, *ErrorDetail) {
+	entry, errDetail := p.lookup(ctx, toolName)
+	if errDetail != nil {
+		return entry, nil, errDetail
+	}
+	tool := entry.tool
+	if !tool.isAvailable(ctx) {
+		return entry, nil, &ErrorDetail{
+			Code:    "tool_unavailable",
+			Message: "Tool is not available: " + toolName,
+		}
+	}
+	params, errDetail = resolveAliases(tool, params)
+	if errDetail != nil {
+		return entry, nil, errDetail
+	}
+	if p.groupParams != nil && entry.key.groupID != "" {
+		params = p.groupParams(ctx, entry.key.groupID, params)
+	}
+	params = applyDefaults(tool, preprocess(tool, params))
+	if errDetail := p.checkParams(ctx, tool, params); errDetail != nil {
+		return entry, nil, errDetail
+	}
+	return entry, params, nil
+}
+
+// execute runs the before hooks and the executor wrapped in the executor
+// middleware, converting errors and panics into an error response.
+func (p *SimpleProvider) execute(ctx context.Context, tool *Tool, executor ToolExecutor, params map[string]interface{}) (resp *ExecuteResponse) {
+	defer func() {
+		if r := recover(); r != nil {
+			resp = p.recovered(ctx, tool.Name, r)
+		}
+	}()
+
+	for _, hook := range p.beforeHooks {
+		if err := hook(ctx, tool.Name, params); err != nil {
+			return &ExecuteResponse{Error: hookError(err)}
+		}
 	}
 
-	result, err := executor(ctx, params)
+	for i := len(p.middleware) - 1; i >= 0; i-- {
+		executor = p.middleware[i](executor)
+	}
+	result, err := executor(WithToolName(ctx, tool.Name), params)
 	if err != nil {
-		return &ExecuteResponse{
-			Error: &ErrorDetail{
-				Code:    "execution_error",
-				Message: err.Error(),
-			},
-		}, nil
+		return &ExecuteResponse{Error: p.mapError(tool, err)}
 	}
 
-	return &ExecuteResponse{
-		Result: result,
-	}, nil
+	return p.checkOutput(ctx, tool, p.applyResultTemplate(ctx, tool, contentResponse(result)))
+}
+
+// recovered logs a panic with its stack trace and returns an internal_error
+// response.
+func (p *SimpleProvider) recovered(ctx context.Context, toolName string, r interface{}) *ExecuteResponse {
+	p.logger.ErrorContext(ctx, "tool executor panicked",
+		"tool", toolName,
+		"panic", r,
+		"request_id", RequestIDFromContext(ctx),
+		"stack", string(debug.Stack()),
+	)
+
+	detail := &ErrorDetail{
+		Code:    "internal_error",
+		Message: "Internal error while executing " + toolName,
+	}
+	if p.debugPanics {
+		detail.Debug = fmt.Sprint(r)
+	}
+	return &ExecuteResponse{Error: detail}
+}
+
+// mapError converts an executor error using the tool's mapper, then the
+// provider's, falling back to execution_error. An *ErrorDetail returned by
+// the executor is passed through as is.
+func (p *SimpleProvider) mapError(tool *Tool, err error) *ErrorDetail {
+	var detail *ErrorDetail
+	if errors.As(err, &detail) {
+		return detail
+	}
+	if detail := ChainErrorMappers(tool.errorMapper, p.errorMapper)(err); detail != nil {
+		return detail
+	}
+	return &ErrorDetail{
+		Code:    "execution_error",
+		Message: err.Error(),
+	}
 }
 
+// hookError passes an *ErrorDetail returned by a hook through unchanged and
+// wraps any other error as an execution_error.
+func hookError(err error) *ErrorDetail {
+	var detail *ErrorDetail
+	if errors.As(err, &detail) {
+		return detail
+	}
+	return &ErrorDetail{
+		Code:    "execution_error",
+		Message: err.Error(),
+	}
+}
+
+// GroupRefreshHandler recomputes groups named by a group_refresh meta
+// response, for example re-counting their tools or reloading them from a source.
+type GroupRefreshHandler func(ctx context.Context, groupIDs []string) error
+
 // GroupProviderImpl extends SimpleProvider with group support.
 type GroupProviderImpl struct {
 	*SimpleProvider
+
+	mu     sync.RWMutex
 	groups map[string]*Group
+
+	// refreshMu serializes refresh handler calls
+	refreshMu sync.Mutex
+	refresh   GroupRefreshHandler
+
+	filter GroupFilterFunc
 }
 
+// GroupFilterFunc decides per request whether a group is visible, typically
+// from the caller's ClaimsFromContext. It must not modify the group.
+type GroupFilterFunc func(ctx context.Context, group *Group) bool
+
 // NewGroupProvider creates a provider with group support.
 func NewGroupProvider(capabilities *Capabilities) *GroupProviderImpl {
 	if capabilities == nil {
@@ -146,23 +677,280 @@ func NewGroupProvider(capabilities *Capabilities) *GroupProviderImpl {
 	}
 	capabilities.WithGroups("")
 
-	return &GroupProviderImpl{
+	p := &GroupProviderImpl{
 		SimpleProvider: NewSimpleProvider(capabilities),
 		groups:         make(map[string]*Group),
 	}
+	p.SimpleProvider.groupParams = p.mergeGroupParams
+	return p
+}
+
+// WithNamespaceRouting files tools registered with a dotted name under the
+// group the namespace names: NewTool("math.add", ...) is registered as tool
+// "add" in group "math", and can be executed as /tools/math.add as well as
+// /groups/math/tools/add. Missing groups are registered along the way, with
+// nested namespaces like "text.case.upper" becoming child groups. Tools
+// given a group with WithGroup keep their names. Enable it before
+// registering tools.
+func (p *GroupProviderImpl) WithNamespaceRouting() *GroupProviderImpl {
+	p.namespace = p.routeNamespace
+	return p
+}
+
+// routeNamespace registers the group of a namespace and its ancestors if
+// they are missing.
+func (p *GroupProviderImpl) routeNamespace(groupID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := ""
+	for _, part := range strings.Split(groupID, ".") {
+		parentID := id
+		id = strings.TrimPrefix(id+"."+part, ".")
+		if _, ok := p.groups[id]; !ok {
+			p.registerGroup(NewGroup(id, part, "").WithParent(parentID))
+		}
+	}
+}
+
+// splitNamespace splits a dotted tool name at its last dot.
+func splitNamespace(toolName string) (namespace, name string, ok bool) {
+	i := strings.LastIndex(toolName, ".")
+	if i <= 0 || i == len(toolName)-1 {
+		return "", toolName, false
+	}
+	return toolName[:i], toolName[i+1:], true
+}
+
+// mergeGroupParams adds the group's injected and default params to the
+// params of a call to one of its tools.
+func (p *GroupProviderImpl) mergeGroupParams(ctx context.Context, groupID string, params map[string]interface{}) map[string]interface{} {
+	p.mu.RLock()
+	group, ok := p.groups[groupID]
+	var defaults map[string]interface{}
+	var injector ParamInjector
+	var callerWins bool
+	if ok {
+		defaults, injector, callerWins = group.DefaultParams, group.injector, group.callerWins
+	}
+	p.mu.RUnlock()
+
+	// The injector runs unlocked, as it may call back into the provider
+	if injector != nil {
+		params = mergeParams(params, injector(ctx), !callerWins)
+	}
+	return mergeParams(params, defaults, false)
 }
 
 // RegisterGroup registers a group.
 func (p *GroupProviderImpl) RegisterGroup(group *Group) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.registerGroup(group)
+}
+
+// registerGroup stores a group. The caller must hold p.mu.
+func (p *GroupProviderImpl) registerGroup(group *Group) {
+	if group.CreatedAt.IsZero() {
+		group.CreatedAt = time.Now()
+	}
 	p.groups[group.ID] = group
 }
 
+// RegisterGroups registers several groups at once. Every group must have a
+// unique ID and a parent that is already registered or part of the batch;
+// otherwise nothing is registered and the first problem is returned.
+func (p *GroupProviderImpl) RegisterGroups(groups ...*Group) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	batch := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		if group.ID == "" {
+			return invalidGroup("group %q has no id", group.Name)
+		}
+		if _, ok := p.groups[group.ID]; ok || batch[group.ID] {
+			return invalidGroup("duplicate group id %q", group.ID)
+		}
+		batch[group.ID] = true
+	}
+
+	for _, group := range groups {
+		if group.ParentID == "" {
+			continue
+		}
+		if _, ok := p.groups[group.ParentID]; !ok && !batch[group.ParentID] {
+			return invalidGroup("group %q has unknown parent %q", group.ID, group.ParentID)
+		}
+	}
+
+	for _, group := range groups {
+		p.registerGroup(group)
+	}
+	return nil
+}
+
+// LoadGroupsJSON registers the groups in a JSON array read from r, as
+// RegisterGroups does.
+func (p *GroupProviderImpl) LoadGroupsJSON(r io.Reader) error {
+	var groups []*Group
+	if err := json.NewDecoder(r).Decode(&groups); err != nil {
+		return invalidGroup("decoding groups: %v", err)
+	}
+	return p.RegisterGroups(groups...)
+}
+
+// invalidGroup returns an invalid_group error.
+func invalidGroup(format string, args ...interface{}) *ErrorDetail {
+	return &ErrorDetail{
+		Code:    "invalid_group",
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// ExecuteGroupTool executes the tool registered under the given group.
+func (p *GroupProviderImpl) ExecuteGroupTool(ctx context.Context, groupID, toolName string, params map[string]interface{}) (*ExecuteResponse, error) {
+	return p.ExecuteTool(WithGroupID(ctx, groupID), toolName, params)
+}
+
+// SetGroupRefreshHandler sets the handler run when an execution returns a
+// group_refresh meta response. It only fires when the capabilities enable
+// both groups and dynamic tools. Handler calls are serialized and made
+// without holding the provider's lock, so the handler may register groups.
+func (p *GroupProviderImpl) SetGroupRefreshHandler(handler GroupRefreshHandler) {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	p.refresh = handler
+}
+
+// SetGroupFilter hides the groups the filter rejects, and their descendants,
+// from callers. Hidden groups are left out of listings and reported as
+// group_not_found, so their existence isn't leaked; their tools are left out
+// of listings and fail to execute with tool_not_found.
+func (p *GroupProviderImpl) SetGroupFilter(filter GroupFilterFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.filter = filter
+	p.groupHidden = func(ctx context.Context, groupID string) bool {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+
+		group, ok := p.groups[groupID]
+		return ok && !p.visible(ctx, group)
+	}
+}
+
+// visible reports whether the group and all its ancestors pass the group
+// filter. The caller must hold p.mu.
+func (p *GroupProviderImpl) visible(ctx context.Context, group *Group) bool {
+	if p.filter == nil {
+		return true
+	}
+
+	seen := make(map[string]bool)
+	for group != nil && !seen[group.ID] {
+		seen[group.ID] = true
+		c := *group
+		if !p.filter(ctx, &c) {
+			return false
+		}
+		group = p.groups[group.ParentID]
+	}
+	return true
+}
+
+// ExecuteTool executes a tool and runs the group refresh handler for a
+// group_refresh meta response. A failing refresh is logged; the response is
+// returned either way.
+func (p *GroupProviderImpl) ExecuteTool(ctx context.Context, toolName string, params map[string]interface{}) (*ExecuteResponse, error) {
+	resp, err := p.SimpleProvider.ExecuteTool(ctx, toolName, params)
+	if err != nil || resp.Meta == nil || resp.Meta.Type != MetaTypeGroupRefresh {
+		return resp, err
+	}
+
+	features := p.capabilities.Features
+	if !features.Groups || !features.DynamicTools {
+		return resp, nil
+	}
+
+	groupIDs, _ := resp.Meta.Data.([]string)
+
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	if p.refresh == nil {
+		return resp, nil
+	}
+	if err := p.refresh(ctx, groupIDs); err != nil {
+		p.logger.ErrorContext(ctx, "group refresh failed",
+			"tool", toolName,
+			"groups", groupIDs,
+			"error", err,
+			"request_id", RequestIDFromContext(ctx),
+		)
+	}
+	return resp, nil
+}
+
+// RefreshToolCounts recounts the tools registered in each group. It can be
+// used as a GroupRefreshHandler.
+func (p *GroupProviderImpl) RefreshToolCounts(ctx context.Context, groupIDs []string) error {
+	counts := make(map[string]int, len(groupIDs))
+	p.catalogMu.RLock()
+	for _, tool := range p.tools {
+		counts[tool.GroupID]++
+	}
+	p.catalogMu.RUnlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, id := range groupIDs {
+		if group, ok := p.groups[id]; ok {
+			group.ToolCount = counts[id]
+		}
+	}
+	return nil
+}
+
+// countTools returns the number of tools registered in a group. The caller
+// must not hold p.mu, which is taken after the catalog lock.
+func (p *GroupProviderImpl) countTools(groupID string) int {
+	p.catalogMu.RLock()
+	defer p.catalogMu.RUnlock()
+
+	count := 0
+	for key := range p.tools {
+		if key.groupID == groupID {
+			count++
+		}
+	}
+	return count
+}
+
 // ListGroups returns all registered groups.
+// With a parent ID, the depth list option selects how many levels of
+// descendants are included.
 func (p *GroupProviderImpl) ListGroups(ctx context.Context, parentID, query string, offset, limit int) (*GroupsResponse, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	candidates := p.groups
+	if parentID != "" {
+		depth := 1
+		if d := ListOptionsFromContext(ctx).Depth; d != nil {
+			depth = *d
+		}
+		candidates = p.descendants(parentID, depth)
+	}
+
 	var groups []Group
-	for _, group := range p.groups {
-		// Filter by parent
-		if parentID != "" && group.ParentID != parentID {
+	for _, group := range candidates {
+		// Hide groups filtered out for the caller
+		if !p.visible(ctx, group) {
 			continue
 		}
 
@@ -173,40 +961,254 @@ func (p *GroupProviderImpl) ListGroups(ctx context.Context, parentID, query stri
 			}
 		}
 
-		groups = append(groups, *group)
+		c := *group
+		localizeGroup(&c, LanguagesFromContext(ctx))
+		groups = append(groups, c)
 	}
 
-	total := len(groups)
+	sortGroups(groups, ListOptionsFromContext(ctx).Sort)
+	groups, total := paginate(groups, offset, limit)
 
-	// Apply pagination
-	if offset >= len(groups) {
-		groups = []Group{}
-	} else {
-		end := offset + limit
-		if limit == 0 || end > len(groups) {
-			end = len(groups)
-		}
-		groups = groups[offset:end]
-	}
+	hasMore, nextOffset := pageCursor(offset, len(groups), total)
 
 	return &GroupsResponse{
-		Groups: groups,
-		Total:  total,
-		Offset: offset,
-		Limit:  limit,
+		Groups:     groups,
+		Total:      total,
+		Offset:     offset,
+		Limit:      limit,
+		HasMore:    hasMore,
+		NextOffset: nextOffset,
 	}, nil
 }
 
-// GetGroup returns a specific group.
+// descendants returns the groups below parentID down to depth levels, or
+// the parent itself for depth 0. A negative depth walks the whole subtree.
+// Each group is visited once, so parent cycles terminate. The caller must
+// hold p.mu.
+func (p *GroupProviderImpl) descendants(parentID string, depth int) map[string]*Group {
+	found := make(map[string]*Group)
+	if depth == 0 {
+		if group, ok := p.groups[parentID]; ok {
+			found[parentID] = group
+		}
+		return found
+	}
+
+	children := make(map[string][]*Group)
+	for _, group := range p.groups {
+		children[group.ParentID] = append(children[group.ParentID], group)
+	}
+
+	visited := map[string]bool{parentID: true}
+	level := []string{parentID}
+	for n := 0; len(level) > 0 && (depth < 0 || n < depth); n++ {
+		var next []string
+		for _, id := range level {
+			for _, child := range children[id] {
+				if visited[child.ID] {
+					continue
+				}
+				visited[child.ID] = true
+				found[child.ID] = child
+				next = append(next, child.ID)
+			}
+		}
+		level = next
+	}
+	return found
+}
+
+// AllGroups returns copies of every registered group, ordered by ID,
+// without pagination or search.
+func (p *GroupProviderImpl) AllGroups() []Group {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	groups := make([]Group, 0, len(p.groups))
+	for _, group := range p.groups {
+		groups = append(groups, *group)
+	}
+	sortGroups(groups, "")
+	return groups
+}
+
+// GetGroup returns a specific group. Its tool count is counted live from the
+// registered tools, unless none are registered in the group yet.
 func (p *GroupProviderImpl) GetGroup(ctx context.Context, groupID string) (*Group, error) {
+	count := p.countTools(groupID)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	group, ok := p.groups[groupID]
-	if !ok {
+	if !ok || !p.visible(ctx, group) {
 		return nil, &ErrorDetail{
 			Code:    "group_not_found",
 			Message: "Group not found: " + groupID,
 		}
 	}
-	return group, nil
+	c := *group
+	if count > 0 {
+		c.ToolCount = count
+	}
+	localizeGroup(&c, LanguagesFromContext(ctx))
+	return &c, nil
+}
+
+// sortTools orders tools by the sort key, defaulting to name. Ties are
+// broken by name and then group so ordering is always deterministic.
+func sortTools(tools []Tool, key string) {
+	desc := strings.HasPrefix(key, "-")
+	key = strings.TrimPrefix(key, "-")
+
+	sort.SliceStable(tools, func(i, j int) bool {
+		a, b := tools[i], tools[j]
+		if desc {
+			a, b = b, a
+		}
+		switch key {
+		case "group":
+			if a.GroupID != b.GroupID {
+				return a.GroupID < b.GroupID
+			}
+		case "created":
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+		case "updated":
+			if !a.UpdatedAt.Equal(b.UpdatedAt) {
+				return a.UpdatedAt.Before(b.UpdatedAt)
+			}
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.GroupID < b.GroupID
+	})
+}
+
+// sortGroups orders groups by the sort key, defaulting to ID. Ties are
+// broken by ID so ordering is always deterministic.
+func sortGroups(groups []Group, key string) {
+	desc := strings.HasPrefix(key, "-")
+	key = strings.TrimPrefix(key, "-")
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if desc {
+			a, b = b, a
+		}
+		switch key {
+		case "name":
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		case "created":
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+		case "order":
+			if a.Order != b.Order {
+				switch {
+				case a.Order == 0:
+					return false
+				case b.Order == 0:
+					return true
+				}
+				return a.Order < b.Order
+			}
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		}
+		return a.ID < b.ID
+	})
+}
+
+// paginate returns the page of items starting at offset, at most limit
+// long, and the total number of items. A zero limit means no limit, and an
+// offset past the end yields an empty, non-nil page.
+func paginate[T any](items []T, offset, limit int) (page []T, total int) {
+	total = len(items)
+	offset = max(offset, 0)
+	if offset >= total {
+		return []T{}, total
+	}
+
+	end := total
+	if limit > 0 && limit < total-offset {
+		end = offset + limit
+	}
+	return items[offset:end], total
+}
+
+// pageCursor reports whether results remain after a page of size count
+// starting at offset, and the offset of the next page if so.
+func pageCursor(offset, count, total int) (bool, int) {
+	next := offset + count
+	if next < total {
+		return true, next
+	}
+	return false, 0
+}
+
+// searchFields are the fields a tool search can match.
+var searchFields = []string{"name", "description", "params"}
+
+// maxSearchDepth bounds how deeply nested parameter schemas are searched.
+const maxSearchDepth = 4
+
+// matchesTool checks if a tool matches a search query in any of the given
+// fields, defaulting to name and description.
+func matchesTool(tool *Tool, query string, fields []string) bool {
+	if len(fields) == 0 {
+		return matchesQuery(tool.Name, tool.Description, query)
+	}
+
+	query = strings.ToLower(query)
+	for _, field := range fields {
+		switch field {
+		case "name":
+			if strings.Contains(strings.ToLower(tool.Name), query) {
+				return true
+			}
+		case "description":
+			if strings.Contains(strings.ToLower(tool.Description), query) {
+				return true
+			}
+		case "params":
+			if matchesSchema(tool.InputSchema, query, 0) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesSchema checks property names and descriptions of an object schema,
+// following nested objects and array items up to maxSearchDepth levels.
+func matchesSchema(schema map[string]interface{}, query string, depth int) bool {
+	if depth >= maxSearchDepth {
+		return false
+	}
+
+	props, _ := schema["properties"].(map[string]interface{})
+	for name, raw := range props {
+		if strings.Contains(strings.ToLower(name), query) {
+			return true
+		}
+		prop, _ := raw.(map[string]interface{})
+		if desc, _ := prop["description"].(string); strings.Contains(strings.ToLower(desc), query) {
+			return true
+		}
+		if matchesSchema(prop, query, depth+1) {
+			return true
+		}
+		if items, ok := prop["items"].(map[string]interface{}); ok && matchesSchema(items, query, depth+1) {
+			return true
+		}
+	}
+	return false
 }
 
 // matchesQuery checks if a name or description matches a search query.
@@ -0,0 +1,240 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestReservedToolNames(t *testing.T) {
+	for name := range reservedToolNames {
+		p := NewGroupProvider(NewCapabilities())
+
+		var detail *ErrorDetail
+		err := p.RegisterToolStrict(NewTool(name, "Shadowed by a fixed route"), echoExecutor)
+		if !errors.As(err, &detail) || detail.Code != "reserved_tool_name" {
+			t.Errorf("ungrouped %s: got %v, want reserved_tool_name", name, err)
+		}
+		if p.HasTool(name) {
+			t.Errorf("ungrouped %s was registered", name)
+		}
+
+		if err := p.RegisterToolStrict(NewTool(name, "Reached through its group").WithGroup("reports"), echoExecutor); err != nil {
+			t.Errorf("grouped %s: %v", name, err)
+		}
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterTool(%s) did not panic", name)
+				}
+			}()
+			p.RegisterTool(NewTool(name, "Shadowed by a fixed route"), echoExecutor)
+		}()
+	}
+}
+
+func TestListToolsReturnsCopies(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	p.RegisterTool(NewTool("add", "Add numbers").
+		WithProperty("a", "number", "First addend", true), echoExecutor)
+	ctx := context.Background()
+
+	resp, err := p.ListTools(ctx, "", "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := resp.Tools[0].InputSchema["properties"].(map[string]interface{})
+	props["a"].(map[string]interface{})["type"] = "string"
+	props["injected"] = map[string]interface{}{"type": "string"}
+	resp.Tools[0].InputSchema["required"] = []string{"injected"}
+
+	resp, err = p.ListTools(ctx, "", "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := resp.Tools[0].InputSchema
+	props = schema["properties"].(map[string]interface{})
+	if _, ok := props["injected"]; ok {
+		t.Error("added property leaked into the provider")
+	}
+	if got := props["a"].(map[string]interface{})["type"]; got != "number" {
+		t.Errorf("property a has type %v, want number", got)
+	}
+	if got := schema["required"]; !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("required = %v, want [a]", got)
+	}
+}
+
+func TestExecutorPanicIsRecovered(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	p.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	p.RegisterTool(NewTool("crash", "Always panics"), func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var m map[string]int
+		m["boom"]++
+		return nil, nil
+	})
+	p.RegisterTool(NewTool("echo", "Echo params"), echoExecutor)
+	h := NewServer(p).Handler()
+
+	for _, debug := range []bool{false, true} {
+		p.SetDebugPanics(debug)
+
+		var resp ExecuteResponse
+		rec := serve(h, "POST", "/tools/crash", "{}")
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("debug=%v: %v: %s", debug, err, rec.Body)
+		}
+		if resp.Error == nil || resp.Error.Code != "internal_error" {
+			t.Fatalf("debug=%v: got error %v, want internal_error", debug, resp.Error)
+		}
+		if (resp.Error.Debug != "") != debug {
+			t.Errorf("debug=%v: debug field %q", debug, resp.Error.Debug)
+		}
+	}
+
+	if rec := serve(h, "POST", "/tools/echo", "{}"); rec.Code != http.StatusOK {
+		t.Errorf("server did not recover: status %d", rec.Code)
+	}
+}
+
+func TestListToolsAcrossGroups(t *testing.T) {
+	p := newTwoGroupProvider()
+	p.RegisterTool(NewTool("compare", "Compare quantities"), echoExecutor)
+	ctx := context.Background()
+
+	type entry struct{ name, group string }
+	listed := func(resp *ToolsResponse) []entry {
+		var got []entry
+		for _, tool := range resp.Tools {
+			got = append(got, entry{tool.Name, tool.GroupID})
+		}
+		return got
+	}
+
+	resp, err := p.ListTools(ctx, "", "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []entry{{"compare", ""}, {"convert", "length"}, {"convert", "weight"}}
+	if got := listed(resp); !reflect.DeepEqual(got, want) || resp.Total != len(want) {
+		t.Errorf("flat listing = %v (total %d), want %v", got, resp.Total, want)
+	}
+
+	resp, err = p.ListTools(ctx, "", "convert", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []entry{{"convert", "length"}, {"convert", "weight"}}
+	if got := listed(resp); !reflect.DeepEqual(got, want) || resp.Total != len(want) {
+		t.Errorf("search = %v (total %d), want %v", got, resp.Total, want)
+	}
+
+	resp, err = p.ListTools(ctx, "weight", "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []entry{{"convert", "weight"}}
+	if got := listed(resp); !reflect.DeepEqual(got, want) || resp.Total != len(want) {
+		t.Errorf("group listing = %v (total %d), want %v", got, resp.Total, want)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		offset, limit int
+		want          []int
+	}{
+		{0, 0, []int{1, 2, 3, 4, 5}},
+		{0, 2, []int{1, 2}},
+		{3, 2, []int{4, 5}},
+		{3, 10, []int{4, 5}},
+		{4, 0, []int{5}},
+		{5, 0, []int{}},
+		{5, 2, []int{}},
+		{100, 1, []int{}},
+		{-1, 2, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		page, total := paginate(items, tt.offset, tt.limit)
+		if !reflect.DeepEqual(page, tt.want) || total != len(items) {
+			t.Errorf("paginate(offset=%d, limit=%d) = %v, %d; want %v, %d", tt.offset, tt.limit, page, total, tt.want, len(items))
+		}
+	}
+
+	if page, total := paginate([]int(nil), 0, 10); page == nil || len(page) != 0 || total != 0 {
+		t.Errorf("paginate(nil) = %#v, %d; want an empty page", page, total)
+	}
+}
+
+func TestNamespaceRoutingOnFailedRegistration(t *testing.T) {
+	p := NewGroupProvider(NewCapabilities().WithGroups("")).WithNamespaceRouting()
+	ctx := context.Background()
+
+	p.RegisterTool(NewTool("math.add", "Add numbers"), echoExecutor)
+
+	dup := NewTool("math.add", "Add numbers again")
+	var detail *ErrorDetail
+	if err := p.RegisterToolStrict(dup, echoExecutor); !errors.As(err, &detail) || detail.Code != "duplicate_tool" {
+		t.Fatalf("got %v, want duplicate_tool", err)
+	}
+	if dup.Name != "math.add" || dup.GroupID != "" {
+		t.Errorf("rejected tool was renamed to %s in group %q", dup.Name, dup.GroupID)
+	}
+
+	bad := NewTool("stats.summary.mean", "Average numbers")
+	bad.ResultTemplate = map[string]string{"mean": "a..b"}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("bad result template did not panic")
+			}
+		}()
+		p.RegisterTool(bad, echoExecutor)
+	}()
+	if bad.Name != "stats.summary.mean" || bad.GroupID != "" {
+		t.Errorf("rejected tool was renamed to %s in group %q", bad.Name, bad.GroupID)
+	}
+	for _, id := range []string{"stats", "stats.summary"} {
+		if _, err := p.GetGroup(ctx, id); err == nil {
+			t.Errorf("group %s was left behind", id)
+		}
+	}
+
+	p.RegisterTool(NewTool("stats.summary.mean", "Average numbers"), echoExecutor)
+	if _, err := p.GetGroup(ctx, "stats.summary"); err != nil {
+		t.Errorf("group stats.summary was not registered: %v", err)
+	}
+}
+
+func TestListingHidesOnlyRegisteredBuiltins(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	p.RegisterTool(NewTool("_internal", "A user tool in the reserved namespace"), echoExecutor)
+	RegisterBuiltins(p)
+
+	names := func(ctx context.Context) []string {
+		resp, err := p.ListTools(ctx, "", "", 0, 0)
+		if err != nil {
+			t.Fatalf("ListTools: %v", err)
+		}
+		var out []string
+		for _, tool := range resp.Tools {
+			out = append(out, tool.Name)
+		}
+		return out
+	}
+
+	if got, want := names(context.Background()), []string{"_internal"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("default listing = %v, want %v", got, want)
+	}
+	ctx := WithListOptions(context.Background(), ListOptions{IncludeBuiltins: true})
+	if got, want := names(ctx), []string{"_echo", "_internal", "_ping"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("listing with builtins = %v, want %v", got, want)
+	}
+}
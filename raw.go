@@ -0,0 +1,98 @@
+package a2t
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// RawQueryParam is the query parameter that asks for a bare result.
+const RawQueryParam = "raw"
+
+// WithRawResults answers successful executions with the bare result instead
+// of the {"result": ...} envelope, as raw=true does per request. Clients
+// can still ask for the envelope with raw=false.
+func WithRawResults() ServerOption {
+	return func(s *Server) {
+		s.rawResults = true
+	}
+}
+
+// rawEnvelope is the part of an execution response rawResults inspects.
+type rawEnvelope struct {
+	Result  json.RawMessage `json:"result"`
+	Content json.RawMessage `json:"content"`
+	Error   json.RawMessage `json:"error"`
+	Meta    json.RawMessage `json:"meta"`
+}
+
+// unwrapResults writes the bare result of a successful execution as the
+// body when the request asked for it: strings as text/plain, other values
+// as JSON. Execution errors are answered as an ErrorResponse with an error
+// status, since there's no envelope to tell them apart from a result; meta
+// responses and content blocks keep the envelope. The raw query parameter
+// is removed before params are read from the query.
+func (s *Server) unwrapResults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := s.rawResults
+		query := r.URL.Query()
+		if value := query.Get(RawQueryParam); value != "" {
+			raw, _ = strconv.ParseBool(value)
+			query.Del(RawQueryParam)
+			r.URL.RawQuery = query.Encode()
+		}
+		if !raw {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buf := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+
+		body := buf.body.Bytes()
+		var envelope rawEnvelope
+		if buf.status != http.StatusOK || json.Unmarshal(body, &envelope) != nil {
+			w.WriteHeader(buf.status)
+			_, _ = w.Write(body)
+			return
+		}
+
+		var detail ErrorDetail
+		if !isJSONNull(envelope.Error) && json.Unmarshal(envelope.Error, &detail) == nil {
+			w.Header().Del("Content-Length")
+			writeError(w, r, rawErrorStatus(&detail), &detail)
+			return
+		}
+
+		if isJSONNull(envelope.Meta) && isJSONNull(envelope.Content) {
+			body = envelope.Result
+			var text string
+			if json.Unmarshal(body, &text) == nil {
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				body = []byte(text)
+			}
+			if len(body) == 0 {
+				body = []byte("null")
+			}
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		}
+
+		w.WriteHeader(buf.status)
+		_, _ = w.Write(body)
+	})
+}
+
+// rawErrorStatus picks the HTTP status of an execution error in a raw
+// response: the error's own Status, or the one for its code.
+func rawErrorStatus(detail *ErrorDetail) int {
+	if detail.Status != 0 {
+		return detail.Status
+	}
+	return errorStatus(detail.Code)
+}
+
+// isJSONNull reports whether a raw JSON value is absent or null.
+func isJSONNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
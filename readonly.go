@@ -0,0 +1,183 @@
+package a2t
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/swaggest/rest/nethttp"
+	"github.com/swaggest/usecase"
+)
+
+// requireReadOnly rejects GET execution of tools not marked ReadOnly, so
+// mutating tools stay POST-only. Unknown tools pass through to execution,
+// which reports them as not found.
+func (s *Server) requireReadOnly(ctx context.Context, toolName string) *ErrorDetail {
+	getter, ok := s.provider.(ToolGetter)
+	if !ok {
+		return &ErrorDetail{
+			Code:    "method_not_allowed",
+			Message: "GET execution requires a provider that can look up tools; use POST",
+			Status:  http.StatusMethodNotAllowed,
+		}
+	}
+
+	tool, err := getter.GetTool(ctx, toolName)
+	if err != nil || tool.ReadOnly {
+		return nil
+	}
+	return &ErrorDetail{
+		Code:    "method_not_allowed",
+		Message: fmt.Sprintf("Tool %s is not read-only; execute it with POST", toolName),
+		Status:  http.StatusMethodNotAllowed,
+	}
+}
+
+// queryRoute serves a GET execution usecase, and HEAD like other GET
+// routes, with the execution response middleware.
+func (s *Server) queryRoute(pattern string, uc usecase.Interactor) {
+	h := nethttp.WrapHandler(nethttp.NewHandler(uc), s.queryReadOnly, s.backpressure, s.timeExecutions, s.unwrapResults)
+	s.service.Method(http.MethodGet, pattern, h)
+	s.service.Method(http.MethodHead, pattern, h)
+}
+
+// queryReadOnly answers GET and HEAD execution of tools that aren't
+// read-only with 405 and "Allow: POST", like other wrong-method requests.
+func (s *Server) queryReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if groupID := chi.URLParam(r, "id"); groupID != "" {
+			ctx = WithGroupID(ctx, groupID)
+		}
+		if detail := s.requireReadOnly(ctx, chi.URLParam(r, "name")); detail != nil {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, r, detail.Status, detail)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// queryToolUsecase executes a read-only tool with params from the query string.
+func (s *Server) queryToolUsecase() usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, in ExecuteToolInput, output *ExecuteResponse) error {
+		ctx, cleanup, err := s.requestContext(ctx, in.request)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+
+		if err := s.requireMatch(ctx, in.Name, in.IfMatch); err != nil {
+			return err
+		}
+
+		params, err := s.resolveParams(ctx, in.Name, in.Params, in.values)
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.execute(ctx, "", in.Name, params)
+		if err != nil {
+			return err
+		}
+
+		*output = *resp
+		return nil
+	})
+
+	u.SetName("queryTool")
+	u.SetTags("Tools")
+	u.SetTitle("Execute Read-Only Tool")
+	u.SetDescription("Executes a read-only tool with parameters from the query string, coerced to the tool's input schema types. Other tools answer 405 and must be executed with POST.")
+
+	return u
+}
+
+// queryGroupToolUsecase executes a read-only group tool with params from the
+// query string.
+func (s *Server) queryGroupToolUsecase() usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, in ExecuteGroupToolInput, output *ExecuteResponse) error {
+		if _, ok := s.provider.(GroupProvider); !ok {
+			return groupsNotServed()
+		}
+
+		ctx, cleanup, err := s.requestContext(ctx, in.request)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
+		ctx = WithGroupID(ctx, in.ID)
+
+		if err := s.requireMatch(ctx, in.Name, in.IfMatch); err != nil {
+			return err
+		}
+
+		params, err := s.resolveParams(ctx, in.Name, in.Params, in.values)
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.execute(ctx, in.ID, in.Name, params)
+		if err != nil {
+			return err
+		}
+
+		*output = *resp
+		return nil
+	})
+
+	u.SetName("queryGroupTool")
+	u.SetTags("Groups", "Tools")
+	u.SetTitle("Execute Read-Only Group Tool")
+	u.SetDescription("Executes a read-only tool within a group context with parameters from the query string. Other tools answer 405 and must be executed with POST.")
+
+	return u
+}
+
+// documentReadOnly lists the tools that accept GET execution on the GET
+// execute operations, as text and as an x-a2t-read-only-tools extension.
+func (s *Server) documentReadOnly(tools []Tool) {
+	flat, grouped := []string{}, []string{}
+	for i := range tools {
+		if !tools[i].ReadOnly {
+			continue
+		}
+		if !containsString(flat, tools[i].Name) {
+			flat = append(flat, tools[i].Name)
+		}
+		if tools[i].GroupID != "" {
+			grouped = append(grouped, qualifiedName(&tools[i]))
+		}
+	}
+	sort.Strings(flat)
+	sort.Strings(grouped)
+
+	caps := s.provider.GetCapabilities()
+	paths := s.service.OpenAPICollector.Reflector().SpecEns().Paths.MapOfPathItemValues
+	annotate := func(path string, names []string) {
+		item, ok := paths[path]
+		if !ok {
+			return
+		}
+		op, ok := item.MapOfOperationValues[strings.ToLower(http.MethodGet)]
+		if !ok {
+			return
+		}
+		description := "No tools are read-only."
+		if len(names) > 0 {
+			description = "Read-only tools: " + strings.Join(names, ", ") + "."
+		}
+		if op.Description != nil {
+			description = *op.Description + "\n\n" + description
+		}
+		op.Description = &description
+		op.WithMapOfAnythingItem("x-a2t-read-only-tools", names)
+		item.MapOfOperationValues[strings.ToLower(http.MethodGet)] = op
+		paths[path] = item
+	}
+	annotate(s.path(caps.Endpoints.Tools+"/{name}"), flat)
+	annotate(s.path(caps.Endpoints.Groups+"/{id}/tools/{name}"), grouped)
+}
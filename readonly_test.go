@@ -0,0 +1,35 @@
+package a2t
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestQueryMutatingToolAllowsPost(t *testing.T) {
+	p := NewGroupProvider(NewCapabilities().WithGroups(""))
+	p.RegisterGroup(NewGroup("billing", "Billing", "Billing tools"))
+	p.RegisterTool(NewTool("lookup", "Look up an invoice").WithReadOnly(), echoExecutor)
+	p.RegisterTool(NewTool("refund", "Refund an invoice"), echoExecutor)
+	p.RegisterTool(NewTool("charge", "Charge a card").WithGroup("billing"), echoExecutor)
+	h := NewServer(p).Handler()
+
+	tests := []struct {
+		method, target string
+		status         int
+		allow          string
+	}{
+		{"GET", "/tools/lookup?id=1", http.StatusOK, ""},
+		{"GET", "/tools/refund?id=1", http.StatusMethodNotAllowed, "POST"},
+		{"HEAD", "/tools/refund?id=1", http.StatusMethodNotAllowed, "POST"},
+		{"GET", "/groups/billing/tools/charge", http.StatusMethodNotAllowed, "POST"},
+	}
+	for _, tt := range tests {
+		rec := serve(h, tt.method, tt.target, "")
+		if rec.Code != tt.status {
+			t.Errorf("%s %s: status %d, want %d", tt.method, tt.target, rec.Code, tt.status)
+		}
+		if got := rec.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("%s %s: Allow %q, want %q", tt.method, tt.target, got, tt.allow)
+		}
+	}
+}
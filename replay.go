@@ -0,0 +1,246 @@
+package a2t
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ReplayRecord captures the inputs of one tool execution, and what it
+// returned, so it can be re-run with ReplayExecution. Params, headers, claim
+// extras and the result are redacted using the server's sensitive keys.
+type ReplayRecord struct {
+	ID        string                 `json:"id"`
+	Time      time.Time              `json:"time"`
+	RequestID string                 `json:"request_id,omitempty"`
+	GroupID   string                 `json:"group_id,omitempty"`
+	Tool      string                 `json:"tool"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	Headers   http.Header            `json:"headers,omitempty"`
+	Claims    *Claims                `json:"claims,omitempty"`
+	Result    interface{}            `json:"result,omitempty"`
+	Error     *ErrorDetail           `json:"error,omitempty"`
+}
+
+// ReplayStore keeps replay records. Save is called after every execution,
+// so it should be quick or hand off to a background writer.
+type ReplayStore interface {
+	Save(ctx context.Context, record ReplayRecord) error
+	Load(ctx context.Context, id string) (*ReplayRecord, error)
+}
+
+// ReplayResult is the outcome of a replayed execution. Changes compares the
+// recorded result and error with the replay's, under "result" and "error";
+// it is empty when the replay behaved the same.
+type ReplayResult struct {
+	Record   *ReplayRecord    `json:"record"`
+	Response *ExecuteResponse `json:"response"`
+	Changes  []Change         `json:"changes,omitempty"`
+}
+
+// WithReplayRecorder saves the inputs and outcome of every tool execution
+// to store, to be re-run later with ReplayExecution. Records carry the
+// request ID the client saw, so a failing call can be looked up from its
+// error. Output of streaming tools isn't recorded.
+func WithReplayRecorder(store ReplayStore) ServerOption {
+	return func(s *Server) {
+		s.replay = store
+	}
+}
+
+type replayingKey struct{}
+
+// recorded runs an execution and saves it to the replay store, if any.
+// Replayed executions aren't recorded again.
+func (s *Server) recorded(ctx context.Context, groupID, toolName string, params map[string]interface{}, run func() (*ExecuteResponse, error)) (*ExecuteResponse, error) {
+	if s.replay == nil || ctx.Value(replayingKey{}) != nil {
+		return run()
+	}
+
+	record := ReplayRecord{
+		ID:        newRequestID(),
+		Time:      time.Now(),
+		RequestID: RequestIDFromContext(ctx),
+		GroupID:   groupID,
+		Tool:      toolName,
+		Params:    s.redact(params),
+	}
+	if info, ok := RequestInfoFromContext(ctx); ok {
+		record.Headers = s.redactHeaders(info.Headers)
+	}
+	if claims := ClaimsFromContext(ctx); claims != nil {
+		record.Claims = &Claims{
+			Subject: claims.Subject,
+			Scopes:  append([]string(nil), claims.Scopes...),
+			Extra:   s.redact(claims.Extra),
+		}
+	}
+
+	resp, err := run()
+
+	record.Error = outcomeError(err)
+	if resp != nil {
+		record.Result = s.redactResult(resp.Result)
+		if resp.Error != nil {
+			record.Error = resp.Error
+		}
+	}
+	if err := s.replay.Save(ctx, record); err != nil {
+		slog.ErrorContext(ctx, "saving replay record", "tool", toolName, "error", err)
+	}
+	return resp, err
+}
+
+// redactHeaders returns a copy of headers without the values of sensitive
+// ones.
+func (s *Server) redactHeaders(headers http.Header) http.Header {
+	if headers == nil {
+		return nil
+	}
+	out := headers.Clone()
+	for name := range out {
+		if s.isSensitive(name) {
+			out[name] = []string{redactedValue}
+		}
+	}
+	return out
+}
+
+// redactResult returns the JSON form of a result with sensitive values
+// replaced.
+func (s *Server) redactResult(result interface{}) interface{} {
+	var value interface{}
+	if err := remarshal(result, &value); err != nil {
+		return nil
+	}
+	return s.redactValue(value)
+}
+
+// ReplayExecution re-runs a recorded execution against the server's current
+// provider, with the recorded group, params, headers and claims, and
+// compares the outcome with the recorded one. Redacted values are replayed
+// as the redaction marker. The replay goes through the same pipeline as
+// Invoke, but isn't recorded itself.
+func (s *Server) ReplayExecution(ctx context.Context, id string) (*ReplayResult, error) {
+	if s.replay == nil {
+		return nil, featureNotSupported("Replay recording is not enabled")
+	}
+	record, err := s.replay.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, replayingKey{}, true)
+	if record.Headers != nil {
+		ctx = WithRequestInfo(ctx, &RequestInfo{Headers: record.Headers.Clone()})
+	}
+	if record.Claims != nil {
+		ctx = WithClaims(ctx, record.Claims)
+	}
+
+	var resp *ExecuteResponse
+	if record.GroupID != "" {
+		resp, err = s.InvokeGroup(ctx, record.GroupID, record.Tool, deepCopyMap(record.Params))
+	} else {
+		resp, err = s.Invoke(ctx, record.Tool, deepCopyMap(record.Params))
+	}
+	if err != nil {
+		resp = &ExecuteResponse{Error: outcomeError(err)}
+	}
+
+	return &ReplayResult{
+		Record:   record,
+		Response: resp,
+		Changes:  diffJSON(replayOutcome(record.Result, record.Error), replayOutcome(s.redactResult(resp.Result), resp.Error)),
+	}, nil
+}
+
+// outcomeError returns the ErrorDetail a client would see for err, or nil.
+func outcomeError(err error) *ErrorDetail {
+	if err == nil {
+		return nil
+	}
+	var detail *ErrorDetail
+	if errors.As(err, &detail) {
+		return detail
+	}
+	return &ErrorDetail{Code: "internal_error", Message: err.Error()}
+}
+
+// replayOutcome is the part of an execution compared by ReplayExecution.
+// Request IDs differ between runs, so they're left out.
+func replayOutcome(result interface{}, detail *ErrorDetail) map[string]interface{} {
+	outcome := map[string]interface{}{"result": result}
+	if detail != nil {
+		outcome["error"] = &ErrorDetail{Code: detail.Code, Message: detail.Message, Details: detail.Details}
+	}
+	return outcome
+}
+
+// MemoryReplayStore keeps the most recent replay records in memory.
+type MemoryReplayStore struct {
+	mu       sync.Mutex
+	capacity int
+	records  map[string]ReplayRecord
+	order    []string
+}
+
+// NewMemoryReplayStore creates a store holding up to capacity records,
+// dropping the oldest beyond that. A capacity of 0 or less keeps 1000.
+func NewMemoryReplayStore(capacity int) *MemoryReplayStore {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &MemoryReplayStore{
+		capacity: capacity,
+		records:  make(map[string]ReplayRecord),
+	}
+}
+
+// Save stores the record, evicting the oldest one when full.
+func (m *MemoryReplayStore) Save(ctx context.Context, record ReplayRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.order) >= m.capacity {
+		delete(m.records, m.order[0])
+		m.order = m.order[1:]
+	}
+	m.records[record.ID] = record
+	m.order = append(m.order, record.ID)
+	return nil
+}
+
+// Load returns the record with the ID, or a replay_not_found error.
+func (m *MemoryReplayStore) Load(ctx context.Context, id string) (*ReplayRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record, ok := m.records[id]
+	if !ok {
+		return nil, &ErrorDetail{
+			Code:    "replay_not_found",
+			Message: fmt.Sprintf("Replay record not found: %s", id),
+		}
+	}
+	return &record, nil
+}
+
+// ByRequestID returns the records of the executions of a request, oldest
+// first.
+func (m *MemoryReplayStore) ByRequestID(requestID string) []ReplayRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var records []ReplayRecord
+	for _, id := range m.order {
+		if record := m.records[id]; record.RequestID == requestID {
+			records = append(records, record)
+		}
+	}
+	return records
+}
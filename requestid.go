@@ -0,0 +1,62 @@
+package a2t
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// RequestIDHeader carries the correlation ID of a request and its response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds incoming request IDs; longer or non-printable
+// IDs are replaced with a generated one.
+const maxRequestIDLength = 128
+
+// requestID reads the incoming request ID, or generates one, stores it in
+// the request context and echoes it in the response header.
+func (s *Server) requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID reports whether a client-supplied ID can be reused.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random version 4 UUID.
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// withRequestID returns a copy of detail tagged with the request ID in ctx.
+func withRequestID(ctx context.Context, detail *ErrorDetail) *ErrorDetail {
+	id := RequestIDFromContext(ctx)
+	if detail == nil || id == "" {
+		return detail
+	}
+	tagged := *detail
+	tagged.RequestID = id
+	return &tagged
+}
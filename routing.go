@@ -0,0 +1,170 @@
+package a2t
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TrailingSlash selects how paths with a trailing slash, such as "/tools/",
+// are routed.
+type TrailingSlash int
+
+const (
+	// TrailingSlashStrict routes paths exactly as registered, so "/tools/"
+	// is not found. It is the default.
+	TrailingSlashStrict TrailingSlash = iota
+
+	// TrailingSlashRedirect redirects to the path without the slash, with
+	// 301 for GET and HEAD and 308, which keeps the method and body, for
+	// other methods.
+	TrailingSlashRedirect
+
+	// TrailingSlashAccept serves the path as if it had no trailing slash.
+	TrailingSlashAccept
+)
+
+// WithTrailingSlash sets how paths with a trailing slash are routed, for
+// clients that call "/tools/" instead of "/tools". Paths already matching a
+// route, such as "/docs/", are never changed.
+func WithTrailingSlash(mode TrailingSlash) ServerOption {
+	return func(s *Server) {
+		s.trailingSlash = mode
+	}
+}
+
+// WithCaseInsensitiveRoutes matches the fixed segments of routes, such as
+// "tools" and "groups", regardless of case, so "/Tools" is served as
+// "/tools". Tool names, group IDs and other path parameters stay case
+// sensitive, and paths already matching a route are never changed.
+func WithCaseInsensitiveRoutes() ServerOption {
+	return func(s *Server) {
+		s.caseInsensitiveRoutes = true
+	}
+}
+
+// normalizePath rewrites or redirects requests for paths with no route to
+// the route they differ from only by a trailing slash or the case of fixed
+// segments, as configured.
+func (s *Server) normalizePath(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.routed(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		escaped := r.URL.EscapedPath()
+		normalized := escaped
+		trimmed := false
+		if s.trailingSlash != TrailingSlashStrict && len(normalized) > 1 && strings.HasSuffix(normalized, "/") {
+			normalized = "/" + strings.Trim(normalized, "/")
+			trimmed = true
+		}
+		if s.caseInsensitiveRoutes {
+			normalized = s.matchCase(normalized)
+		}
+
+		path, err := url.PathUnescape(normalized)
+		if normalized == escaped || err != nil || !s.routed(path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		u := *r.URL
+		u.Path, u.RawPath = path, normalized
+		if trimmed && s.trailingSlash == TrailingSlashRedirect {
+			code := http.StatusPermanentRedirect
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				code = http.StatusMovedPermanently
+			}
+			http.Redirect(w, r, u.RequestURI(), code)
+			return
+		}
+
+		r = r.WithContext(r.Context())
+		r.URL = &u
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routed reports whether any route serves the path, with any method.
+func (s *Server) routed(path string) bool {
+	for _, method := range routeMethods {
+		if s.service.Match(chi.NewRouteContext(), method, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCase rewrites the fixed segments of an escaped path to the case of
+// the route pattern whose fixed segments it matches most, leaving
+// parameters as they are. Paths matching no pattern are returned unchanged.
+func (s *Server) matchCase(escaped string) string {
+	segments := strings.Split(escaped, "/")
+
+	var best []string
+	bestScore := -1
+	for _, pattern := range s.routePatterns {
+		score, ok := matchSegments(pattern, segments)
+		if ok && score > bestScore {
+			best, bestScore = pattern, score
+		}
+	}
+	if best == nil {
+		return escaped
+	}
+
+	for i, segment := range best {
+		if segment == "*" {
+			break
+		}
+		if !isParamSegment(segment) {
+			segments[i] = segment
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// matchSegments matches path segments against a route pattern's, fixed
+// segments regardless of case. It returns the number of fixed segments.
+func matchSegments(pattern, segments []string) (int, bool) {
+	score := 0
+	for i, segment := range pattern {
+		if segment == "*" {
+			return score, len(segments) >= i
+		}
+		if i >= len(segments) {
+			return 0, false
+		}
+		if isParamSegment(segment) {
+			continue
+		}
+		if !strings.EqualFold(segment, segments[i]) {
+			return 0, false
+		}
+		score++
+	}
+	return score, len(segments) == len(pattern)
+}
+
+// isParamSegment reports whether a route pattern segment is a parameter,
+// such as "{name}".
+func isParamSegment(segment string) bool {
+	return strings.HasPrefix(segment, "{")
+}
+
+// collectRoutePatterns records the registered route patterns, split into
+// segments, for case-insensitive matching.
+func (s *Server) collectRoutePatterns() {
+	seen := make(map[string]bool)
+	_ = chi.Walk(s.service, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if !seen[route] {
+			seen[route] = true
+			s.routePatterns = append(s.routePatterns, strings.Split(route, "/"))
+		}
+		return nil
+	})
+}
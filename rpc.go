@@ -0,0 +1,360 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSON-RPC 2.0 error codes. The standard codes cover malformed requests;
+// the others report a2t error codes.
+const (
+	RPCParseError     = -32700
+	RPCInvalidRequest = -32600
+	RPCMethodNotFound = -32601
+	RPCInvalidParams  = -32602
+	RPCInternalError  = -32603
+
+	RPCExecutionError      = -32000
+	RPCToolNotFound        = -32001
+	RPCToolUnavailable     = -32002
+	RPCAmbiguousTool       = -32003
+	RPCUnauthorized        = -32004
+	RPCForbidden           = -32005
+	RPCOverloaded          = -32006
+	RPCPayloadTooLarge     = -32007
+	RPCFeatureNotSupported = -32008
+	RPCToolNotInGroup      = -32009
+)
+
+// rpcErrorCodes maps ErrorDetail codes to JSON-RPC error codes. Unlisted
+// codes map to RPCExecutionError.
+var rpcErrorCodes = map[string]int{
+	"invalid_params":     RPCInvalidParams,
+	"invalid_deadline":   RPCInvalidParams,
+	"internal_error":     RPCInternalError,
+	"tool_not_found":     RPCToolNotFound,
+	"tool_unavailable":   RPCToolUnavailable,
+	"ambiguous_tool":     RPCAmbiguousTool,
+	"unauthorized":       RPCUnauthorized,
+	"insufficient_scope": RPCForbidden,
+	"overloaded":         RPCOverloaded,
+	"payload_too_large":  RPCPayloadTooLarge,
+
+	"feature_not_supported": RPCFeatureNotSupported,
+	"tool_not_in_group":     RPCToolNotInGroup,
+}
+
+// RPCRequest is a JSON-RPC 2.0 request or notification.
+type RPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// RPCResponse is a JSON-RPC 2.0 response.
+type RPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is a JSON-RPC error object. Data holds the a2t ErrorDetail.
+type RPCError struct {
+	Code    int          `json:"code"`
+	Message string       `json:"message"`
+	Data    *ErrorDetail `json:"data,omitempty"`
+}
+
+// rpcListToolsParams are the params of the listTools method.
+type rpcListToolsParams struct {
+	GroupID         string `json:"group_id"`
+	Q               string `json:"q"`
+	SearchFields    string `json:"search_fields"`
+	ReadOnly        bool   `json:"read_only"`
+	IncludeBuiltins bool   `json:"include_builtins"`
+	Highlight       bool   `json:"highlight"`
+	Sort            string `json:"sort"`
+	Offset          int    `json:"offset"`
+	Limit           int    `json:"limit"`
+}
+
+// rpcListGroupsParams are the params of the listGroups method.
+type rpcListGroupsParams struct {
+	Q        string `json:"q"`
+	ParentID string `json:"parent_id"`
+	Depth    *int   `json:"depth"`
+	Sort     string `json:"sort"`
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+}
+
+// rpcExecuteToolParams are the params of the executeTool method.
+type rpcExecuteToolParams struct {
+	Name    string                 `json:"name"`
+	GroupID string                 `json:"group_id"`
+	Params  map[string]interface{} `json:"params"`
+}
+
+// WithRPC serves JSON-RPC 2.0 at the endpoint, "/rpc" by default, with the
+// listTools, listGroups, executeTool and getCapabilities methods.
+func (c *Capabilities) WithRPC(endpoint string) *Capabilities {
+	if endpoint == "" {
+		endpoint = "/rpc"
+	}
+	c.Endpoints.RPC = endpoint
+	return c
+}
+
+// serveRPC answers a JSON-RPC request or batch. Methods run through the same
+// usecases as the REST endpoints; batch entries run concurrently, as many at
+// a time as the calls of a batch execution.
+func (s *Server) serveRPC(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxUploadSize)
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		writeRPC(w, rpcFailure(nil, RPCParseError, "Parse error: "+err.Error()))
+		return
+	}
+
+	// A batch is an array of requests answered with an array of responses
+	if len(raw) > 0 && raw[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(raw, &batch); err != nil || len(batch) == 0 {
+			writeRPC(w, rpcFailure(nil, RPCInvalidRequest, "Invalid request"))
+			return
+		}
+		if err := checkBatchSize(len(batch), s.effectiveLimits(r.Context()).MaxBatchCalls); err != nil {
+			writeRPC(w, &RPCResponse{JSONRPC: "2.0", Error: rpcErrorFrom(r.Context(), err)})
+			return
+		}
+
+		responses := make([]*RPCResponse, len(batch))
+		runBounded(len(batch), func(i int) {
+			responses[i] = s.handleRPCMessage(r, batch[i])
+		})
+
+		var out []*RPCResponse
+		for _, resp := range responses {
+			if resp != nil {
+				out = append(out, resp)
+			}
+		}
+		if len(out) == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeRPC(w, out)
+		return
+	}
+
+	resp := s.handleRPCMessage(r, raw)
+	if resp == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeRPC(w, resp)
+}
+
+// handleRPCMessage decodes and answers a single request. It returns nil for
+// notifications.
+func (s *Server) handleRPCMessage(r *http.Request, msg json.RawMessage) *RPCResponse {
+	var req RPCRequest
+	if err := json.Unmarshal(msg, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+		return rpcFailure(req.ID, RPCInvalidRequest, "Invalid request")
+	}
+
+	result, err := s.callRPC(r, &req)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	if err != nil {
+		return &RPCResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErrorFrom(r.Context(), err)}
+	}
+	return &RPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+// callRPC dispatches a request to the usecase behind its method.
+func (s *Server) callRPC(r *http.Request, req *RPCRequest) (interface{}, error) {
+	ctx := r.Context()
+	caps := s.provider.GetCapabilities()
+
+	switch req.Method {
+	case "getCapabilities":
+		return s.capabilitiesDocument(ctx), nil
+
+	case "listTools":
+		var params rpcListToolsParams
+		if err := decodeRPCParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		output := &ToolsResponse{}
+		if params.GroupID != "" {
+			if !caps.Features.Groups {
+				return nil, groupsNotServed()
+			}
+			in := ListGroupToolsInput{
+				ID: params.GroupID, Q: params.Q, SearchFields: params.SearchFields,
+				ReadOnly: params.ReadOnly, IncludeBuiltins: params.IncludeBuiltins, Highlight: params.Highlight,
+				Sort: params.Sort, Offset: params.Offset, Limit: params.Limit,
+			}
+			if err := validateQuery(in); err != nil {
+				return nil, err
+			}
+			err := s.listGroupToolsUsecase().Interact(ctx, in, output)
+			return output, err
+		}
+		if caps.Features.GroupOnly {
+			return nil, &ErrorDetail{Code: "invalid_params", Message: "group_id is required in group-only mode"}
+		}
+		in := ListToolsInput{
+			Q: params.Q, SearchFields: params.SearchFields,
+			ReadOnly: params.ReadOnly, IncludeBuiltins: params.IncludeBuiltins, Highlight: params.Highlight,
+			Sort: params.Sort, Offset: params.Offset, Limit: params.Limit,
+		}
+		if err := validateQuery(in); err != nil {
+			return nil, err
+		}
+		err := s.listToolsUsecase().Interact(ctx, in, output)
+		return output, err
+
+	case "listGroups":
+		if !caps.Features.Groups {
+			return nil, groupsNotServed()
+		}
+		var params rpcListGroupsParams
+		if err := decodeRPCParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		depth := 1
+		if params.Depth != nil {
+			depth = *params.Depth
+		}
+		in := ListGroupsInput{
+			Q: params.Q, ParentID: params.ParentID, Depth: depth,
+			Sort: params.Sort, Offset: params.Offset, Limit: params.Limit,
+		}
+		if err := validateQuery(in); err != nil {
+			return nil, err
+		}
+		output := &GroupsResponse{}
+		err := s.listGroupsUsecase().Interact(ctx, in, output)
+		return output, err
+
+	case "executeTool":
+		var params rpcExecuteToolParams
+		if err := decodeRPCParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.Name == "" {
+			return nil, &ErrorDetail{Code: "invalid_params", Message: "name is required"}
+		}
+
+		if params.GroupID == "" && caps.Features.GroupOnly {
+			return nil, &ErrorDetail{Code: "invalid_params", Message: "group_id is required in group-only mode"}
+		}
+		result := s.batchCall(ctx, r, BatchCall{Name: params.Name, GroupID: params.GroupID, Params: params.Params})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		return &result.ExecuteResponse, nil
+
+	default:
+		return nil, &rpcMethodNotFound{method: req.Method}
+	}
+}
+
+// validateQuery checks the enum and minimum tags of a listing input's query
+// fields, as request validation does for the REST endpoints.
+func validateQuery(in interface{}) error {
+	v := reflect.ValueOf(in)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("query")
+		if name == "" {
+			continue
+		}
+		value := v.Field(i)
+
+		if enum, ok := field.Tag.Lookup("enum"); ok && value.Kind() == reflect.String && value.String() != "" {
+			allowed := strings.Split(enum, ",")
+			if !containsString(allowed, value.String()) {
+				return &ErrorDetail{
+					Code:    "invalid_params",
+					Message: fmt.Sprintf("%s must be one of %s, got %q", name, strings.Join(allowed, ", "), value.String()),
+				}
+			}
+		}
+		if minimum, ok := field.Tag.Lookup("minimum"); ok && value.Kind() == reflect.Int {
+			if m, err := strconv.ParseInt(minimum, 10, 64); err == nil && value.Int() < m {
+				return &ErrorDetail{
+					Code:    "invalid_params",
+					Message: fmt.Sprintf("%s must be at least %d, got %d", name, m, value.Int()),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// rpcMethodNotFound reports an unknown JSON-RPC method.
+type rpcMethodNotFound struct {
+	method string
+}
+
+func (e *rpcMethodNotFound) Error() string {
+	return "Method not found: " + e.method
+}
+
+// decodeRPCParams decodes by-name params. Absent params decode to the zero value.
+func decodeRPCParams(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &ErrorDetail{Code: "invalid_params", Message: fmt.Sprintf("Invalid params: %s", err.Error())}
+	}
+	return nil
+}
+
+// rpcErrorFrom converts an error into a JSON-RPC error object, carrying the
+// ErrorDetail it wraps as data.
+func rpcErrorFrom(ctx context.Context, err error) *RPCError {
+	var notFound *rpcMethodNotFound
+	if errors.As(err, &notFound) {
+		return &RPCError{Code: RPCMethodNotFound, Message: notFound.Error()}
+	}
+
+	var detail *ErrorDetail
+	if !errors.As(err, &detail) {
+		// Errors without a detail come from request validation
+		return &RPCError{Code: RPCInvalidParams, Message: err.Error()}
+	}
+
+	code, ok := rpcErrorCodes[detail.Code]
+	if !ok {
+		code = RPCExecutionError
+	}
+	return &RPCError{Code: code, Message: detail.Message, Data: withRequestID(ctx, detail)}
+}
+
+// rpcFailure builds an error response.
+func rpcFailure(id json.RawMessage, code int, message string) *RPCResponse {
+	return &RPCResponse{JSONRPC: "2.0", ID: id, Error: &RPCError{Code: code, Message: message}}
+}
+
+// writeRPC writes a JSON-RPC response body. JSON-RPC errors travel in the
+// body, so the status is always 200.
+func writeRPC(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,77 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRPCValidatesListParams(t *testing.T) {
+	p := NewGroupProvider(NewCapabilities().WithGroups("").WithRPC(""))
+	p.RegisterGroup(NewGroup("math", "Math", "Math tools"))
+	p.RegisterTool(NewTool("add", "Add numbers").WithGroup("math"), echoExecutor)
+	h := NewServer(p).Handler()
+
+	tests := []struct {
+		method, params string
+		code           int
+	}{
+		{"listTools", `{"sort": "bogus"}`, RPCInvalidParams},
+		{"listTools", `{"group_id": "math", "sort": "bogus"}`, RPCInvalidParams},
+		{"listGroups", `{"sort": "group"}`, RPCInvalidParams},
+		{"listGroups", `{"depth": -2}`, RPCInvalidParams},
+		{"listTools", `{"sort": "-name"}`, 0},
+		{"listGroups", `{"sort": "-id", "depth": -1}`, 0},
+	}
+	for _, tt := range tests {
+		body := `{"jsonrpc": "2.0", "id": 1, "method": "` + tt.method + `", "params": ` + tt.params + `}`
+		var resp RPCResponse
+		if err := json.Unmarshal(serve(h, "POST", "/rpc", body).Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.params, err)
+		}
+		var code int
+		if resp.Error != nil {
+			code = resp.Error.Code
+		}
+		if code != tt.code {
+			t.Errorf("%s %s: error %+v, want code %d", tt.method, tt.params, resp.Error, tt.code)
+		}
+	}
+}
+
+func TestRPCBatchBoundsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	p := NewSimpleProvider(NewCapabilities().WithRPC(""))
+	p.RegisterTool(NewTool("slow", "Sleep briefly"), func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil, nil
+	})
+	h := NewServer(p).Handler()
+
+	calls := strings.Repeat(`{"jsonrpc": "2.0", "id": 1, "method": "executeTool", "params": {"name": "slow"}},`, 3*batchConcurrency)
+	rec := serve(h, "POST", "/rpc", "["+strings.TrimSuffix(calls, ",")+"]")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("batch status %d: %s", rec.Code, rec.Body)
+	}
+	var responses []RPCResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &responses); err != nil || len(responses) != 3*batchConcurrency {
+		t.Fatalf("got %d responses (%v), want %d", len(responses), err, 3*batchConcurrency)
+	}
+	if peak > batchConcurrency {
+		t.Errorf("%d calls ran at once, want at most %d", peak, batchConcurrency)
+	}
+}
@@ -0,0 +1,47 @@
+package a2t
+
+import "context"
+
+// SchemaValidator checks a JSON-decoded value against a JSON Schema. It
+// validates tool params and, with ValidateOutput, results, so a server can
+// share validation semantics with other services.
+type SchemaValidator interface {
+	Validate(schema map[string]interface{}, value interface{}) error
+}
+
+// BuiltinValidator is the default SchemaValidator. It covers type, enum,
+// const, format, pattern, minimum and maximum, properties, required,
+// boolean additionalProperties, items, allOf and if/then/else.
+type BuiltinValidator struct{}
+
+// Validate checks value against schema.
+func (BuiltinValidator) Validate(schema map[string]interface{}, value interface{}) error {
+	return validateValue(schema, value, "")
+}
+
+// WithValidator replaces the built-in schema validator for param and output
+// validation of the calls the server handles.
+func WithValidator(validator SchemaValidator) ServerOption {
+	return func(s *Server) {
+		s.validator = validator
+	}
+}
+
+type schemaValidatorKey struct{}
+
+// withSchemaValidator records the server's validator for the provider.
+func (s *Server) withSchemaValidator(ctx context.Context) context.Context {
+	if s.validator == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, schemaValidatorKey{}, s.validator)
+}
+
+// schemaValidatorFromContext returns the validator for the call in ctx,
+// BuiltinValidator unless the server was given another.
+func schemaValidatorFromContext(ctx context.Context) SchemaValidator {
+	if v, ok := ctx.Value(schemaValidatorKey{}).(SchemaValidator); ok {
+		return v
+	}
+	return BuiltinValidator{}
+}
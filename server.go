@@ -2,74 +2,313 @@ package a2t
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/rest"
+	"github.com/swaggest/rest/nethttp"
 	"github.com/swaggest/rest/web"
 	swgui "github.com/swaggest/swgui/v5emb"
 	"github.com/swaggest/usecase"
+	"github.com/swaggest/usecase/status"
 )
 
 // Server is an HTTP server that exposes a ToolProvider with OpenAPI documentation.
 type Server struct {
-	provider ToolProvider
-	service  *web.Service
+	provider           ToolProvider
+	service            *web.Service
+	exposedHeaders     map[string]bool
+	capabilitiesMaxAge time.Duration
+	limiter            *executionLimiter
+	groupLimiters      map[groupLimiterKey]groupLimiter
+	groupLimitersMu    sync.Mutex
+	tiers              CapabilitiesFunc
+	tierLimiters       tierLimiters
+	results            *resultBuffer
+	validator          SchemaValidator
+	timingHeaders      bool
+	rawResults         bool
+	compressionMinSize int
+	auth               AuthValidator
+	maxUploadSize      int64
+	basePath           string
+	audit              AuditSink
+	replay             ReplayStore
+	sensitiveKeys      []string
+	signingKey         ed25519.PrivateKey
+	acceptedMediaTypes []string
+	problemDetails     bool
+	problemTypeBase    string
+
+	// capabilitiesHandler serves the capabilities document, whose body the
+	// signature covers
+	capabilitiesHandler http.Handler
+
+	trailingSlash         TrailingSlash
+	caseInsensitiveRoutes bool
+	routePatterns         [][]string
+
+	experimental        []ExperimentalFeature
+	experimentalEnabled bool
+
+	startedAt time.Time
+	inFlight  atomic.Int64
+}
+
+// DefaultCapabilitiesMaxAge is how long clients may cache the capabilities document.
+const DefaultCapabilitiesMaxAge = 5 * time.Minute
+
+// ServerOption configures optional Server behavior.
+type ServerOption func(*Server)
+
+// WithExposedHeaders lets the named sensitive headers (such as Authorization)
+// reach executors through RequestInfo. They are withheld by default.
+func WithExposedHeaders(names ...string) ServerOption {
+	return func(s *Server) {
+		for _, name := range names {
+			s.exposedHeaders[http.CanonicalHeaderKey(name)] = true
+		}
+	}
 }
 
 // ListToolsInput represents input for listing tools.
 type ListToolsInput struct {
-	Q      string `query:"q" description:"Search query to filter tools by name or description"`
-	Offset int    `query:"offset" description:"Pagination offset"`
-	Limit  int    `query:"limit" description:"Maximum number of tools to return" default:"100"`
+	Q               string `query:"q" description:"Search query to filter tools by name or description"`
+	SearchFields    string `query:"search_fields" description:"Comma-separated fields the search query matches: name, description, params" example:"name,params"`
+	ReadOnly        bool   `query:"read_only" description:"Only return read-only tools"`
+	IncludeBuiltins bool   `query:"include_builtins" description:"Include built-in diagnostic tools such as _ping"`
+	Highlight       bool   `query:"highlight" description:"Report the name and description spans matching q"`
+	Sort            string `query:"sort" description:"Sort key, prefix with - for descending order" enum:"name,-name,group,-group,created,-created,updated,-updated"`
+	Offset          int    `query:"offset" description:"Pagination offset"`
+	Limit           int    `query:"limit" description:"Maximum number of tools to return, limits.default_tools_limit when omitted"`
 }
 
 // ListGroupsInput represents input for listing groups.
 type ListGroupsInput struct {
 	Q        string `query:"q" description:"Search query to filter groups by name or description"`
 	ParentID string `query:"parent_id" description:"Filter groups by parent ID"`
+	Depth    int    `query:"depth" description:"Levels of descendants of parent_id to include: 1 for direct children, 0 for only the parent, -1 for all" default:"1" minimum:"-1"`
+	Sort     string `query:"sort" description:"Sort key, prefix with - for descending order" enum:"name,-name,id,-id,created,-created,order,-order"`
 	Offset   int    `query:"offset" description:"Pagination offset"`
-	Limit    int    `query:"limit" description:"Maximum number of groups to return" default:"50"`
+	Limit    int    `query:"limit" description:"Maximum number of groups to return, limits.default_groups_limit when omitted"`
 }
 
 // ExecuteToolInput represents input for executing a tool.
 type ExecuteToolInput struct {
-	Name   string                 `path:"name" description:"Tool name"`
-	Params map[string]interface{} `json:"-"` // Body
+	Name    string                 `path:"name" description:"Tool name"`
+	Params  map[string]interface{} `json:"-"` // Body
+	IfMatch string                 `header:"If-Match" description:"Tool ETag the call expects; it fails with 412 if the tool has changed"`
+
+	request *http.Request
+	values  url.Values
+}
+
+// LoadFromHTTPRequest decodes the tool name and JSON body parameters.
+func (in *ExecuteToolInput) LoadFromHTTPRequest(r *http.Request) error {
+	in.Name = chi.URLParam(r, "name")
+	in.IfMatch = r.Header.Get("If-Match")
+	in.request = r
+
+	params, values, err := decodeParams(r)
+	if err != nil {
+		return err
+	}
+	in.Params = params
+	in.values = values
+	return nil
+}
+
+// GetGroupInput represents input for getting a single group.
+type GetGroupInput struct {
+	ID      string `path:"id" description:"Group ID"`
+	Include string `query:"include" description:"Set to ancestors to include the group's parent chain" enum:"ancestors"`
 }
 
 // ListGroupToolsInput represents input for listing tools in a group.
 type ListGroupToolsInput struct {
-	ID     string `path:"id" description:"Group ID"`
-	Q      string `query:"q" description:"Search query to filter tools by name or description"`
-	Offset int    `query:"offset" description:"Pagination offset"`
-	Limit  int    `query:"limit" description:"Maximum number of tools to return" default:"100"`
+	ID              string `path:"id" description:"Group ID"`
+	Q               string `query:"q" description:"Search query to filter tools by name or description"`
+	SearchFields    string `query:"search_fields" description:"Comma-separated fields the search query matches: name, description, params" example:"name,params"`
+	ReadOnly        bool   `query:"read_only" description:"Only return read-only tools"`
+	IncludeBuiltins bool   `query:"include_builtins" description:"Include built-in diagnostic tools such as _ping"`
+	Highlight       bool   `query:"highlight" description:"Report the name and description spans matching q"`
+	Sort            string `query:"sort" description:"Sort key, prefix with - for descending order" enum:"name,-name,group,-group,created,-created,updated,-updated"`
+	Offset          int    `query:"offset" description:"Pagination offset"`
+	Limit           int    `query:"limit" description:"Maximum number of tools to return, limits.default_tools_limit when omitted"`
 }
 
 // ExecuteGroupToolInput represents input for executing a tool in a group.
 type ExecuteGroupToolInput struct {
-	ID     string                 `path:"id" description:"Group ID"`
-	Name   string                 `path:"name" description:"Tool name"`
-	Params map[string]interface{} `json:"-"` // Body
+	ID      string                 `path:"id" description:"Group ID"`
+	Name    string                 `path:"name" description:"Tool name"`
+	Params  map[string]interface{} `json:"-"` // Body
+	IfMatch string                 `header:"If-Match" description:"Tool ETag the call expects; it fails with 412 if the tool has changed"`
+
+	request *http.Request
+	values  url.Values
+}
+
+// LoadFromHTTPRequest decodes the group ID, tool name and JSON body parameters.
+func (in *ExecuteGroupToolInput) LoadFromHTTPRequest(r *http.Request) error {
+	in.ID = chi.URLParam(r, "id")
+	in.Name = chi.URLParam(r, "name")
+	in.IfMatch = r.Header.Get("If-Match")
+	in.request = r
+
+	params, values, err := decodeParams(r)
+	if err != nil {
+		return err
+	}
+	in.Params = params
+	in.values = values
+	return nil
+}
+
+// decodeParams reads tool parameters from the request. JSON bodies are the
+// default and are decoded directly. Form-encoded and multipart bodies, or
+// the query string when the body is empty, are returned as raw values so
+// they can be coerced against the tool's input schema.
+func decodeParams(r *http.Request) (map[string]interface{}, url.Values, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	if mediaType == "multipart/form-data" {
+		values, err := decodeMultipart(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, values, nil
+	} else if mediaType == "application/x-www-form-urlencoded" {
+		if err := r.ParseForm(); err != nil {
+			return nil, nil, &ErrorDetail{
+				Code:    "invalid_params",
+				Message: "Invalid form body: " + err.Error(),
+			}
+		}
+		if len(r.PostForm) > 0 {
+			return nil, r.PostForm, nil
+		}
+	} else if r.Body != nil {
+		var params map[string]interface{}
+		err := json.NewDecoder(r.Body).Decode(&params)
+		if err != nil && err != io.EOF {
+			return nil, nil, &ErrorDetail{
+				Code:    "invalid_json",
+				Message: "Invalid JSON body: " + err.Error(),
+			}
+		}
+		if err == nil {
+			if params == nil {
+				params = make(map[string]interface{})
+			}
+			return params, nil, nil
+		}
+	}
+
+	// Empty body: fall back to query parameters for simple clients
+	if query := r.URL.Query(); len(query) > 0 {
+		return nil, query, nil
+	}
+	return make(map[string]interface{}), nil, nil
+}
+
+// WithBasePath mounts every route, including the capabilities document and
+// docs, under prefix (for example "/api/v1/agent"), so the handler can be
+// registered on an existing mux without stripping the prefix.
+func WithBasePath(prefix string) ServerOption {
+	return func(s *Server) {
+		s.basePath = "/" + strings.Trim(prefix, "/")
+		if s.basePath == "/" {
+			s.basePath = ""
+		}
+	}
+}
+
+// path returns an endpoint path under the server's base path.
+func (s *Server) path(p string) string {
+	return s.basePath + p
+}
+
+// WithCapabilitiesMaxAge sets the Cache-Control max-age sent with the
+// capabilities document. A zero or negative duration disables caching.
+func WithCapabilitiesMaxAge(maxAge time.Duration) ServerOption {
+	return func(s *Server) {
+		s.capabilitiesMaxAge = maxAge
+	}
 }
 
 // NewServer creates a new a2t HTTP server with OpenAPI documentation.
-func NewServer(provider ToolProvider) *Server {
+// It panics if the provider's capabilities fail ValidateCapabilities.
+func NewServer(provider ToolProvider, opts ...ServerOption) *Server {
+	if err := ValidateCapabilities(provider); err != nil {
+		panic("a2t: " + err.Error())
+	}
+
 	service := web.NewService(openapi3.NewReflector())
 
+	// Every GET route also answers HEAD with the same headers and no body
+	service.AddHeadToGet = true
+
 	// Set API information
 	service.OpenAPISchema().SetTitle("a2t - Agent-to-Tool Protocol")
 	service.OpenAPISchema().SetDescription("A simple, stateless protocol for tool calling between AI agents and tool providers")
 	service.OpenAPISchema().SetVersion("1.0.0")
 
 	s := &Server{
-		provider: provider,
-		service:  service,
+		provider:           provider,
+		service:            service,
+		exposedHeaders:     make(map[string]bool),
+		capabilitiesMaxAge: DefaultCapabilitiesMaxAge,
+		maxUploadSize:      DefaultMaxUploadSize,
+		sensitiveKeys:      DefaultSensitiveKeys,
+		acceptedMediaTypes: DefaultAcceptedMediaTypes,
+		problemTypeBase:    DefaultProblemTypeBase,
+		groupLimiters:      make(map[groupLimiterKey]groupLimiter),
+		startedAt:          time.Now(),
 	}
 
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.limiter = newExecutionLimiter(provider.GetCapabilities().Limits)
+	s.results = newResultBuffer(provider.GetCapabilities().Limits)
+
+	service.Use(s.requestID)
+	if s.trailingSlash != TrailingSlashStrict || s.caseInsensitiveRoutes {
+		service.Use(s.normalizePath)
+	}
+	service.Use(s.negotiateProblems, s.acceptLanguage, s.enforceMediaType, s.limitUploads)
+	if s.auth != nil {
+		service.Use(s.authenticate)
+	}
+	if s.compressionMinSize > 0 {
+		service.Use(s.compress)
+	}
+
+	// Errors carrying an ErrorDetail are written as an ErrorResponse
+	service.Wrap(nethttp.OptionsMiddleware(func(h *nethttp.Handler) {
+		h.MakeErrResp = makeErrResp
+	}))
+
 	// Register routes
 	s.registerRoutes()
+	if s.caseInsensitiveRoutes {
+		s.collectRoutePatterns()
+	}
+	s.documentTools()
+	s.nameHeadOperations()
 
 	return s
 }
@@ -78,31 +317,199 @@ func (s *Server) registerRoutes() {
 	caps := s.provider.GetCapabilities()
 
 	// Well-known capabilities endpoint
-	s.service.Get("/.well-known/a2t-capabilities.json", s.capabilitiesUsecase())
+	capsHandler := nethttp.WrapHandler(nethttp.NewHandler(s.capabilitiesUsecase()), s.cacheControl)
+	s.capabilitiesHandler = capsHandler
+	s.service.Method(http.MethodGet, s.path(caps.Endpoints.WellKnownPath()), capsHandler)
+	s.service.Method(http.MethodHead, s.path(caps.Endpoints.WellKnownPath()), capsHandler)
+	if s.signingKey != nil {
+		sigHandler := s.cacheControl(http.HandlerFunc(s.capabilitiesSignature))
+		s.service.Method(http.MethodGet, s.path(caps.Endpoints.WellKnownPath()+SignatureSuffix), sigHandler)
+		s.service.Method(http.MethodHead, s.path(caps.Endpoints.WellKnownPath()+SignatureSuffix), sigHandler)
+	}
 
-	// Tools endpoints
-	s.service.Get(caps.Endpoints.Tools, s.listToolsUsecase())
-	s.service.Post(caps.Endpoints.Tools+"/{name}", s.executeToolUsecase())
+	// Tools endpoints, omitted in group-only mode
+	if !caps.Features.GroupOnly {
+		s.service.Get(s.path(caps.Endpoints.Tools), s.listToolsUsecase())
+		s.exportRoute(s.path(caps.Endpoints.Tools + "/export"))
+		s.service.Method(http.MethodPost, s.path(caps.Endpoints.Tools+"/batch"),
+			nethttp.WrapHandler(nethttp.NewHandler(s.batchExecuteUsecase()), s.limitBody))
+		s.service.Method(http.MethodPost, s.path(caps.Endpoints.Tools+"/{name}"),
+			nethttp.WrapHandler(nethttp.NewHandler(s.executeToolUsecase()), s.backpressure, s.streamTools, s.timeExecutions, s.unwrapResults))
+		s.queryRoute(s.path(caps.Endpoints.Tools+"/{name}"), s.queryToolUsecase())
+		s.service.Get(s.path(caps.Endpoints.Tools+"/{name}/results/{cursor}"), s.resultPageUsecase())
+		s.helpRoute(s.path(caps.Endpoints.Tools+"/{name}/help"), s.toolHelpUsecase())
+	}
 
 	// Group endpoints (if enabled)
 	if caps.Features.Groups {
-		s.service.Get(caps.Endpoints.Groups, s.listGroupsUsecase())
-		s.service.Get(caps.Endpoints.Groups+"/{id}/tools", s.listGroupToolsUsecase())
-		s.service.Post(caps.Endpoints.Groups+"/{id}/tools/{name}", s.executeGroupToolUsecase())
+		s.service.Get(s.path(caps.Endpoints.Groups), s.listGroupsUsecase())
+		s.service.Get(s.path(caps.Endpoints.Groups+"/{id}"), s.getGroupUsecase())
+		s.service.Get(s.path(caps.Endpoints.Groups+"/{id}/tools"), s.listGroupToolsUsecase())
+		s.service.Method(http.MethodPost, s.path(caps.Endpoints.Groups+"/{id}/tools/{name}"),
+			nethttp.WrapHandler(nethttp.NewHandler(s.executeGroupToolUsecase()), s.backpressure, s.streamTools, s.timeExecutions, s.unwrapResults))
+		s.queryRoute(s.path(caps.Endpoints.Groups+"/{id}/tools/{name}"), s.queryGroupToolUsecase())
+		s.service.Get(s.path(caps.Endpoints.Groups+"/{id}/tools/{name}/results/{cursor}"), s.groupResultPageUsecase())
+		s.helpRoute(s.path(caps.Endpoints.Groups+"/{id}/tools/{name}/help"), s.groupToolHelpUsecase())
+	}
+
+	// JSON-RPC endpoint (if enabled)
+	if caps.Endpoints.RPC != "" {
+		s.service.Method(http.MethodPost, s.path(caps.Endpoints.RPC), http.HandlerFunc(s.serveRPC))
 	}
 
+	// Status endpoint (if enabled)
+	if caps.Endpoints.Status != "" {
+		s.service.Get(s.path(caps.Endpoints.Status), s.statusUsecase())
+	}
+
+	// Preview endpoints (if enabled)
+	s.registerExperimental()
+
 	// Swagger UI endpoint
-	s.service.Docs("/docs", swgui.New)
+	s.service.Docs(s.path("/docs"), swgui.New)
+
+	// Unknown paths and wrong-method requests get structured errors too
+	s.service.NotFound(notFound)
+	s.service.MethodNotAllowed(s.methodNotAllowed)
+}
+
+// routeMethods are the methods checked when building the Allow header.
+var routeMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// methodNotAllowed responds with 405, an Allow header listing the methods
+// registered for the path, and an ErrorResponse body.
+func (s *Server) methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	var allowed []string
+	for _, method := range routeMethods {
+		if s.service.Match(chi.NewRouteContext(), method, r.URL.Path) {
+			allowed = append(allowed, method)
+		}
+	}
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+
+	writeError(w, r, http.StatusMethodNotAllowed, &ErrorDetail{
+		Code:    "method_not_allowed",
+		Message: fmt.Sprintf("Method %s not allowed on %s", r.Method, r.URL.Path),
+	})
+}
+
+// notFound responds with 404 and an ErrorResponse body, instead of the
+// router's plain-text page.
+func notFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusNotFound, &ErrorDetail{
+		Code:    "not_found",
+		Message: fmt.Sprintf("No route for %s %s", r.Method, r.URL.Path),
+	})
+}
+
+// writeError writes an ErrorResponse, or a problem document when the request
+// asked for one, with the given HTTP status. The detail is tagged with the
+// request ID.
+func writeError(w http.ResponseWriter, r *http.Request, status int, detail *ErrorDetail) {
+	var body interface{} = ErrorResponse{Error: withRequestID(r.Context(), detail)}
+	if problem, ok := problemFor(r.Context(), status, detail); ok {
+		body = problem
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// makeErrResp writes errors that wrap an *ErrorDetail as an ErrorResponse,
+// answered with the detail's Status, else the code set with status.Wrap,
+// else the status for its error code. Other errors, such as request
+// decoding and validation failures, are reported as invalid_params when
+// they are the caller's fault and internal_error otherwise. Requests that
+// asked for problem documents get one for every error.
+func makeErrResp(ctx context.Context, err error) (int, interface{}) {
+	code, _ := rest.Err(err)
+
+	var detail *ErrorDetail
+	if errors.As(err, &detail) {
+		if detail.Status != 0 {
+			code = detail.Status
+		} else if code == http.StatusInternalServerError {
+			code = errorStatus(detail.Code)
+		}
+	} else if code >= http.StatusBadRequest && code < http.StatusInternalServerError {
+		detail = &ErrorDetail{Code: "invalid_params", Message: err.Error(), Status: code}
+	} else {
+		detail = &ErrorDetail{Code: "internal_error", Message: err.Error()}
+	}
+
+	if problem, ok := problemFor(ctx, code, detail); ok {
+		return code, problem
+	}
+	return code, ErrorResponse{Error: withRequestID(ctx, detail)}
+}
+
+// cacheControl sets Cache-Control on capabilities responses so agents don't
+// need to re-fetch a document that rarely changes.
+func (s *Server) cacheControl(next http.Handler) http.Handler {
+	value := "no-cache"
+	if s.capabilitiesMaxAge > 0 {
+		// Tiered documents differ per caller and must not be shared
+		visibility := "public"
+		if s.tiers != nil {
+			visibility = "private"
+		}
+		value = fmt.Sprintf("%s, max-age=%d", visibility, int(s.capabilitiesMaxAge.Seconds()))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", value)
+		if s.tiers != nil {
+			w.Header().Add("Vary", "Authorization")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// capabilitiesDocument returns the capabilities as served to the caller in
+// ctx, with effective limits and endpoints under the base path.
+func (s *Server) capabilitiesDocument(ctx context.Context) *Capabilities {
+	caps := s.provider.GetCapabilities()
+	doc := *s.capabilitiesFor(ctx)
+	doc.Endpoints = caps.Endpoints
+	doc.Limits = s.effectiveLimits(ctx)
+	doc.Experimental = s.experimentalFeatures()
+
+	// Advertise endpoints as mounted under the base path
+	if s.basePath != "" {
+		if caps.Endpoints.Tools != "" {
+			doc.Endpoints.Tools = s.path(caps.Endpoints.Tools)
+		}
+		if caps.Endpoints.Groups != "" {
+			doc.Endpoints.Groups = s.path(caps.Endpoints.Groups)
+		}
+		if caps.Endpoints.RPC != "" {
+			doc.Endpoints.RPC = s.path(caps.Endpoints.RPC)
+		}
+		if caps.Endpoints.Status != "" {
+			doc.Endpoints.Status = s.path(caps.Endpoints.Status)
+		}
+		doc.Endpoints.WellKnown = s.path(caps.Endpoints.WellKnownPath())
+	}
+	return &doc
 }
 
 // capabilitiesUsecase returns the server's capabilities.
 func (s *Server) capabilitiesUsecase() usecase.Interactor {
 	u := usecase.NewInteractor(func(ctx context.Context, input struct{}, output *Capabilities) error {
-		caps := s.provider.GetCapabilities()
-		*output = *caps
+		*output = *s.capabilitiesDocument(ctx)
 		return nil
 	})
 
+	u.SetName("getCapabilities")
 	u.SetTags("Capabilities")
 	u.SetTitle("Get Capabilities")
 	u.SetDescription("Returns the server's capabilities including supported features and endpoints")
@@ -113,10 +520,26 @@ func (s *Server) capabilitiesUsecase() usecase.Interactor {
 // listToolsUsecase lists all available tools.
 func (s *Server) listToolsUsecase() usecase.Interactor {
 	u := usecase.NewInteractor(func(ctx context.Context, input ListToolsInput, output *ToolsResponse) error {
+		if err := validatePage(input.Offset, input.Limit); err != nil {
+			return err
+		}
+
+		limits := s.effectiveLimits(ctx)
 		limit := input.Limit
 		if limit == 0 {
-			limit = 100
+			limit = limits.DefaultToolsLimit
+		}
+		limit = min(limit, limits.MaxToolsPerRequest)
+
+		fields, err := parseSearchFields(input.SearchFields)
+		if err != nil {
+			return err
 		}
+		ctx = WithListOptions(ctx, ListOptions{
+			Sort: input.Sort, SearchFields: fields,
+			ReadOnly: input.ReadOnly, IncludeBuiltins: input.IncludeBuiltins,
+			Highlight: input.Highlight,
+		})
 
 		resp, err := s.provider.ListTools(ctx, "", input.Q, input.Offset, limit)
 		if err != nil {
@@ -127,6 +550,7 @@ func (s *Server) listToolsUsecase() usecase.Interactor {
 		return nil
 	})
 
+	u.SetName("listTools")
 	u.SetTags("Tools")
 	u.SetTitle("List Tools")
 	u.SetDescription("Returns all available tools with optional search filtering")
@@ -136,19 +560,23 @@ func (s *Server) listToolsUsecase() usecase.Interactor {
 
 // executeToolUsecase executes a specific tool.
 func (s *Server) executeToolUsecase() usecase.Interactor {
-	type input struct {
-		ExecuteToolInput
-		Body map[string]interface{}
-	}
+	u := usecase.NewInteractor(func(ctx context.Context, in ExecuteToolInput, output *ExecuteResponse) error {
+		ctx, cleanup, err := s.requestContext(ctx, in.request)
+		defer cleanup()
+		if err != nil {
+			return err
+		}
 
-	u := usecase.NewInteractor(func(ctx context.Context, in input, output *ExecuteResponse) error {
-		// Use Body from the request
-		params := in.Body
-		if params == nil {
-			params = make(map[string]interface{})
+		if err := s.requireMatch(ctx, in.Name, in.IfMatch); err != nil {
+			return err
 		}
 
-		resp, err := s.provider.ExecuteTool(ctx, in.Name, params)
+		params, err := s.resolveParams(ctx, in.Name, in.Params, in.values)
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.execute(ctx, "", in.Name, params)
 		if err != nil {
 			return err
 		}
@@ -157,6 +585,7 @@ func (s *Server) executeToolUsecase() usecase.Interactor {
 		return nil
 	})
 
+	u.SetName("executeTool")
 	u.SetTags("Tools")
 	u.SetTitle("Execute Tool")
 	u.SetDescription("Executes a specific tool with the provided parameters")
@@ -169,13 +598,21 @@ func (s *Server) listGroupsUsecase() usecase.Interactor {
 	u := usecase.NewInteractor(func(ctx context.Context, input ListGroupsInput, output *GroupsResponse) error {
 		groupProvider, ok := s.provider.(GroupProvider)
 		if !ok {
-			return fmt.Errorf("groups not supported")
+			return groupsNotServed()
+		}
+
+		if err := validatePage(input.Offset, input.Limit); err != nil {
+			return err
 		}
 
+		limits := s.effectiveLimits(ctx)
 		limit := input.Limit
 		if limit == 0 {
-			limit = 50
+			limit = limits.DefaultGroupsLimit
 		}
+		limit = min(limit, limits.MaxGroupsPerRequest)
+
+		ctx = WithListOptions(ctx, ListOptions{Sort: input.Sort, Depth: &input.Depth})
 
 		resp, err := groupProvider.ListGroups(ctx, input.ParentID, input.Q, input.Offset, limit)
 		if err != nil {
@@ -186,6 +623,7 @@ func (s *Server) listGroupsUsecase() usecase.Interactor {
 		return nil
 	})
 
+	u.SetName("listGroups")
 	u.SetTags("Groups")
 	u.SetTitle("List Groups")
 	u.SetDescription("Returns all available groups with optional search filtering")
@@ -193,18 +631,101 @@ func (s *Server) listGroupsUsecase() usecase.Interactor {
 	return u
 }
 
+// getGroupUsecase returns a single group, optionally with its ancestors.
+func (s *Server) getGroupUsecase() usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input GetGroupInput, output *GroupResponse) error {
+		groupProvider, ok := s.provider.(GroupProvider)
+		if !ok {
+			return groupsNotServed()
+		}
+
+		group, err := groupProvider.GetGroup(ctx, input.ID)
+		if err != nil {
+			return groupNotFound(err)
+		}
+		output.Group = *group
+
+		if input.Include == "ancestors" {
+			ancestors, err := ancestorsOf(ctx, groupProvider, group)
+			if err != nil {
+				return err
+			}
+			output.Ancestors = ancestors
+		}
+		return nil
+	})
+
+	u.SetName("getGroup")
+	u.SetTags("Groups")
+	u.SetTitle("Get Group")
+	u.SetDescription("Returns a single group with its tool count, optionally with its parent chain")
+	u.SetExpectedErrors(status.NotFound)
+
+	return u
+}
+
+// ancestorsOf returns the parent chain of a group, root first. A missing
+// parent ends the chain, and so does a parent cycle.
+func ancestorsOf(ctx context.Context, provider GroupProvider, group *Group) ([]Group, error) {
+	var chain []Group
+	seen := map[string]bool{group.ID: true}
+	for parentID := group.ParentID; parentID != "" && !seen[parentID]; {
+		seen[parentID] = true
+		parent, err := provider.GetGroup(ctx, parentID)
+		if err != nil {
+			var detail *ErrorDetail
+			if errors.As(err, &detail) && detail.Code == "group_not_found" {
+				break
+			}
+			return nil, err
+		}
+		chain = append(chain, *parent)
+		parentID = parent.ParentID
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+// groupNotFound answers group_not_found errors with 404.
+func groupNotFound(err error) error {
+	var detail *ErrorDetail
+	if errors.As(err, &detail) && detail.Code == "group_not_found" {
+		return status.Wrap(err, status.NotFound)
+	}
+	return err
+}
+
 // listGroupToolsUsecase lists tools in a specific group.
 func (s *Server) listGroupToolsUsecase() usecase.Interactor {
 	u := usecase.NewInteractor(func(ctx context.Context, input ListGroupToolsInput, output *ToolsResponse) error {
 		groupProvider, ok := s.provider.(GroupProvider)
 		if !ok {
-			return fmt.Errorf("groups not supported")
+			return groupsNotServed()
+		}
+
+		if err := validatePage(input.Offset, input.Limit); err != nil {
+			return err
 		}
 
+		limits := s.effectiveLimits(ctx)
 		limit := input.Limit
 		if limit == 0 {
-			limit = 100
+			limit = limits.DefaultToolsLimit
 		}
+		limit = min(limit, limits.MaxToolsPerRequest)
+
+		fields, err := parseSearchFields(input.SearchFields)
+		if err != nil {
+			return err
+		}
+		ctx = WithListOptions(ctx, ListOptions{
+			Sort: input.Sort, SearchFields: fields,
+			ReadOnly: input.ReadOnly, IncludeBuiltins: input.IncludeBuiltins,
+			Highlight: input.Highlight,
+		})
 
 		resp, err := groupProvider.ListTools(ctx, input.ID, input.Q, input.Offset, limit)
 		if err != nil {
@@ -215,6 +736,7 @@ func (s *Server) listGroupToolsUsecase() usecase.Interactor {
 		return nil
 	})
 
+	u.SetName("listGroupTools")
 	u.SetTags("Groups", "Tools")
 	u.SetTitle("List Group Tools")
 	u.SetDescription("Returns all tools in a specific group with optional search filtering")
@@ -224,24 +746,28 @@ func (s *Server) listGroupToolsUsecase() usecase.Interactor {
 
 // executeGroupToolUsecase executes a tool within a specific group.
 func (s *Server) executeGroupToolUsecase() usecase.Interactor {
-	type input struct {
-		ExecuteGroupToolInput
-		Body map[string]interface{}
-	}
+	u := usecase.NewInteractor(func(ctx context.Context, in ExecuteGroupToolInput, output *ExecuteResponse) error {
+		if _, ok := s.provider.(GroupProvider); !ok {
+			return groupsNotServed()
+		}
 
-	u := usecase.NewInteractor(func(ctx context.Context, in input, output *ExecuteResponse) error {
-		groupProvider, ok := s.provider.(GroupProvider)
-		if !ok {
-			return fmt.Errorf("groups not supported")
+		ctx, cleanup, err := s.requestContext(ctx, in.request)
+		defer cleanup()
+		if err != nil {
+			return err
 		}
+		ctx = WithGroupID(ctx, in.ID)
 
-		// Use Body from the request
-		params := in.Body
-		if params == nil {
-			params = make(map[string]interface{})
+		if err := s.requireMatch(ctx, in.Name, in.IfMatch); err != nil {
+			return err
 		}
 
-		resp, err := groupProvider.ExecuteTool(ctx, in.Name, params)
+		params, err := s.resolveParams(ctx, in.Name, in.Params, in.values)
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.execute(ctx, in.ID, in.Name, params)
 		if err != nil {
 			return err
 		}
@@ -250,6 +776,7 @@ func (s *Server) executeGroupToolUsecase() usecase.Interactor {
 		return nil
 	})
 
+	u.SetName("executeGroupTool")
 	u.SetTags("Groups", "Tools")
 	u.SetTitle("Execute Group Tool")
 	u.SetDescription("Executes a specific tool within a group context")
@@ -257,6 +784,180 @@ func (s *Server) executeGroupToolUsecase() usecase.Interactor {
 	return u
 }
 
+// parseSearchFields splits a comma-separated search_fields value, rejecting
+// unknown fields.
+func parseSearchFields(raw string) ([]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if !containsString(searchFields, field) {
+			return nil, status.Wrap(&ErrorDetail{
+				Code:    "invalid_params",
+				Message: fmt.Sprintf("Unknown search field %q, expected one of: %s", field, strings.Join(searchFields, ", ")),
+			}, status.InvalidArgument)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// effectiveLimits returns the limits the server enforces for the caller in
+// ctx: the configured limits with defaults filled in for unset page sizes
+// and caps.
+func (s *Server) effectiveLimits(ctx context.Context) *LimitsConfig {
+	var limits LimitsConfig
+	if configured := s.capabilitiesFor(ctx).Limits; configured != nil {
+		limits = *configured
+	}
+
+	if limits.MaxToolsPerRequest <= 0 {
+		limits.MaxToolsPerRequest = DefaultMaxToolsPerRequest
+	}
+	if limits.MaxGroupsPerRequest <= 0 {
+		limits.MaxGroupsPerRequest = DefaultMaxGroupsPerRequest
+	}
+	if limits.MaxBatchCalls <= 0 {
+		limits.MaxBatchCalls = DefaultMaxBatchCalls
+	}
+	if limits.DefaultToolsLimit <= 0 {
+		limits.DefaultToolsLimit = DefaultToolsPageSize
+	}
+	if limits.DefaultGroupsLimit <= 0 {
+		limits.DefaultGroupsLimit = DefaultGroupsPageSize
+	}
+	limits.DefaultToolsLimit = min(limits.DefaultToolsLimit, limits.MaxToolsPerRequest)
+	limits.DefaultGroupsLimit = min(limits.DefaultGroupsLimit, limits.MaxGroupsPerRequest)
+	concurrency := limits.MaxConcurrentExecutions > 0 || limits.MaxConcurrentPerTool > 0
+	if concurrency && limits.OverloadBehavior == "" {
+		limits.OverloadBehavior = OverloadReject
+		if limits.QueueTimeout > 0 {
+			limits.OverloadBehavior = OverloadQueue
+		}
+	}
+	return &limits
+}
+
+// validatePage rejects negative pagination parameters. An offset past the
+// end of the results is allowed and yields an empty page.
+func validatePage(offset, limit int) error {
+	if offset < 0 || limit < 0 {
+		return status.Wrap(&ErrorDetail{
+			Code:    "invalid_params",
+			Message: fmt.Sprintf("offset and limit must not be negative, got offset=%d limit=%d", offset, limit),
+		}, status.InvalidArgument)
+	}
+	return nil
+}
+
+// execute checks the caller's scopes and runs a tool, within a group when
+// groupID is set, once an execution slot is free. The slot is released even
+// if the executor panics. Every execution is recorded to the audit sink and
+// the replay store.
+func (s *Server) execute(ctx context.Context, groupID, toolName string, params map[string]interface{}) (*ExecuteResponse, error) {
+	return s.pipeline(ctx, groupID, toolName, params, func() (*ExecuteResponse, error) {
+		return s.run(ctx, groupID, toolName, params)
+	})
+}
+
+// pipeline audits, records and tracks an execution, then calls run once the
+// caller is authorized and an execution slot is free. Streamed executions
+// share it with execute.
+func (s *Server) pipeline(ctx context.Context, groupID, toolName string, params map[string]interface{}, run func() (*ExecuteResponse, error)) (*ExecuteResponse, error) {
+	return s.audited(ctx, groupID, toolName, params, func() (*ExecuteResponse, error) {
+		return s.recorded(ctx, groupID, toolName, params, func() (*ExecuteResponse, error) {
+			return s.tracked(func() (*ExecuteResponse, error) {
+				if err := s.authorize(ctx, toolName); err != nil {
+					return nil, err
+				}
+
+				release, err := s.limiterFor(ctx, groupID).acquire(ctx, groupID, toolName)
+				if err != nil {
+					return nil, err
+				}
+				defer release()
+
+				return run()
+			})
+		})
+	})
+}
+
+// run performs an execution for execute.
+func (s *Server) run(ctx context.Context, groupID, toolName string, params map[string]interface{}) (*ExecuteResponse, error) {
+	var resp *ExecuteResponse
+	var err error
+	if groupProvider, ok := s.provider.(GroupProvider); ok && groupID != "" {
+		resp, err = groupProvider.ExecuteGroupTool(ctx, groupID, toolName, params)
+	} else {
+		resp, err = s.provider.ExecuteTool(ctx, toolName, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Error = withRequestID(ctx, resp.Error)
+	if resp.Error != nil && resp.Error.Status != 0 {
+		return nil, resp.Error
+	}
+	if paged, ok := resp.Result.(*PagedResult); ok && resp.Error == nil {
+		resp.Result = s.results.firstPage(ctx, groupID, toolName, paged)
+	}
+	return resp, nil
+}
+
+// resolveParams returns the decoded JSON params, or converts form and query
+// values using the tool's input schema when the provider can look it up.
+func (s *Server) resolveParams(ctx context.Context, toolName string, params map[string]interface{}, values url.Values) (map[string]interface{}, error) {
+	if values == nil {
+		return params, nil
+	}
+
+	var schema map[string]interface{}
+	var aliases map[string]string
+	if getter, ok := s.provider.(ToolGetter); ok {
+		if tool, err := getter.GetTool(ctx, toolName); err == nil {
+			schema, aliases = tool.InputSchema, tool.aliases
+		}
+	}
+
+	params, err := coerceValues(schema, aliases, values)
+	if err != nil {
+		return nil, status.Wrap(err, status.InvalidArgument)
+	}
+	return params, nil
+}
+
+// requestContext attaches the schema validator, request metadata and
+// uploaded files to the context passed to executors and bounds it by the
+// client's deadline. The context is derived from the request's, so it is
+// also cancelled when the client disconnects. The returned func releases the
+// files and the deadline.
+func (s *Server) requestContext(ctx context.Context, r *http.Request) (context.Context, func(), error) {
+	ctx = s.withCapabilities(s.withSchemaValidator(ctx))
+	if r == nil {
+		return ctx, func() {}, nil
+	}
+
+	ctx, cancel, err := withDeadline(ctx, r)
+	if err != nil {
+		return ctx, cancel, err
+	}
+	ctx = WithRequestInfo(ctx, newRequestInfo(r, s.exposedHeaders))
+
+	files, closeFiles := openFiles(r)
+	if files != nil {
+		ctx = WithFiles(ctx, files)
+	}
+	return ctx, func() {
+		closeFiles()
+		cancel()
+	}, nil
+}
+
 // Handler returns the http.Handler for the server.
 func (s *Server) Handler() http.Handler {
 	return s.service
@@ -271,9 +972,9 @@ func (s *Server) ListenAndServe(addr string) error {
 	}
 
 	fmt.Printf("a2t server listening on %s\n", addr)
-	fmt.Printf("Capabilities: http://%s/.well-known/a2t-capabilities.json\n", host)
-	fmt.Printf("OpenAPI JSON: http://%s/docs/openapi.json\n", host)
-	fmt.Printf("Swagger UI: http://%s/docs\n", host)
+	fmt.Printf("Capabilities: http://%s%s\n", host, s.path(s.provider.GetCapabilities().Endpoints.WellKnownPath()))
+	fmt.Printf("OpenAPI JSON: http://%s%s\n", host, s.path("/docs/openapi.json"))
+	fmt.Printf("Swagger UI: http://%s%s\n", host, s.path("/docs"))
 	fmt.Println()
 
 	return http.ListenAndServe(addr, s.service)
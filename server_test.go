@@ -0,0 +1,205 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serve sends a request to h and returns the recorded response. Headers are
+// given as name, value pairs; JSON bodies get a JSON Content-Type.
+func serve(h http.Handler, method, target, body string, headers ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+// echoExecutor returns its params as the result.
+func echoExecutor(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	return params, nil
+}
+
+func TestErrorEnvelopes(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	p.RegisterTool(NewTool("echo", "Echo params"), echoExecutor)
+	h := NewServer(p).Handler()
+
+	tests := []struct {
+		name           string
+		method, target string
+		body           string
+		status         int
+		code           string
+	}{
+		{"unknown route", "GET", "/nowhere", "", http.StatusNotFound, "not_found"},
+		{"unknown tool", "POST", "/tools/missing", "{}", http.StatusOK, "tool_not_found"},
+		{"malformed body", "POST", "/tools/echo", "{", http.StatusBadRequest, "invalid_json"},
+		{"invalid query", "GET", "/tools?sort=bogus", "", http.StatusBadRequest, "invalid_params"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(h, tt.method, tt.target, tt.body)
+			if rec.Code != tt.status {
+				t.Errorf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Error == nil {
+				t.Fatalf("body is not an error envelope: %s", rec.Body)
+			}
+			if resp.Error.Code != tt.code {
+				t.Errorf("code %q, want %q", resp.Error.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	p.RegisterTool(NewTool("echo", "Echo params"), echoExecutor)
+	h := NewServer(p).Handler()
+
+	tests := []struct {
+		method, target string
+	}{
+		{"POST", "/tools"},
+		{"DELETE", "/tools/echo"},
+		{"GET", "/tools/echo"},
+	}
+	for _, tt := range tests {
+		rec := serve(h, tt.method, tt.target, "")
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s %s: status %d, want 405", tt.method, tt.target, rec.Code)
+			continue
+		}
+		allow := rec.Header().Get("Allow")
+		if allow == "" || strings.Contains(allow, tt.method) {
+			t.Errorf("%s %s: Allow %q", tt.method, tt.target, allow)
+		}
+		var resp ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Error == nil {
+			t.Errorf("%s %s: body is not an error envelope: %s", tt.method, tt.target, rec.Body)
+		}
+	}
+}
+
+// newTwoGroupProvider registers a tool named "convert" in both the "length"
+// and "weight" groups, each returning its group ID.
+func newTwoGroupProvider() *GroupProviderImpl {
+	p := NewGroupProvider(NewCapabilities().WithGroups(""))
+	for _, group := range []string{"length", "weight"} {
+		group := group
+		p.RegisterGroup(NewGroup(group, group, "Unit conversions"))
+		p.RegisterTool(NewTool("convert", "Convert units").WithGroup(group),
+			func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				return group, nil
+			})
+	}
+	return p
+}
+
+func TestExecuteGroupToolIsolation(t *testing.T) {
+	h := NewServer(newTwoGroupProvider()).Handler()
+
+	for _, group := range []string{"length", "weight"} {
+		rec := serve(h, "POST", "/groups/"+group+"/tools/convert", "{}")
+		var resp ExecuteResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: %v: %s", group, err, rec.Body)
+		}
+		if resp.Error != nil || resp.Result != group {
+			t.Errorf("%s: got result %v, error %v; want %q", group, resp.Result, resp.Error, group)
+		}
+	}
+
+	rec := serve(h, "POST", "/tools/convert", "{}")
+	var resp ExecuteResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil || resp.Error.Code != "ambiguous_tool" {
+		t.Errorf("ungrouped call: got error %v, want ambiguous_tool", resp.Error)
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	for _, name := range []string{"a", "b", "c"} {
+		p.RegisterTool(NewTool(name, "Tool "+name), echoExecutor)
+	}
+	h := NewServer(p).Handler()
+
+	tests := []struct {
+		query  string
+		status int
+		tools  int
+	}{
+		{"offset=-5", http.StatusBadRequest, 0},
+		{"limit=-1", http.StatusBadRequest, 0},
+		{"offset=-1&limit=-1", http.StatusBadRequest, 0},
+		{"offset=0&limit=0", http.StatusOK, 3},
+		{"offset=1&limit=1", http.StatusOK, 1},
+		{"offset=2&limit=10", http.StatusOK, 1},
+		{"offset=3", http.StatusOK, 0},
+		{"offset=1000000", http.StatusOK, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := serve(h, "GET", "/tools?"+tt.query, "")
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.status != http.StatusOK {
+				var resp ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Error == nil || resp.Error.Code != "invalid_params" {
+					t.Errorf("body %s, want invalid_params", rec.Body)
+				}
+				return
+			}
+			var resp ToolsResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Tools) != tt.tools || resp.Total != 3 {
+				t.Errorf("got %d tools of %d, want %d of 3", len(resp.Tools), resp.Total, tt.tools)
+			}
+		})
+	}
+}
+
+func TestExecuteGroupToolNotInGroup(t *testing.T) {
+	p := newTwoGroupProvider()
+	p.RegisterTool(NewTool("scale", "Scale a length").WithGroup("length"), echoExecutor)
+	h := NewServer(p).Handler()
+
+	tests := []struct {
+		target, code string
+	}{
+		{"/groups/weight/tools/scale", "tool_not_in_group"},
+		{"/groups/weight/tools/missing", "tool_not_found"},
+		{"/groups/length/tools/scale", ""},
+	}
+	for _, tt := range tests {
+		var resp ExecuteResponse
+		rec := serve(h, "POST", tt.target, "{}")
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: %v: %s", tt.target, err, rec.Body)
+		}
+		var code string
+		if resp.Error != nil {
+			code = resp.Error.Code
+		}
+		if code != tt.code {
+			t.Errorf("%s: got error code %q, want %q", tt.target, code, tt.code)
+		}
+	}
+}
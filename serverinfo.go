@@ -0,0 +1,54 @@
+package a2t
+
+import (
+	"context"
+	"time"
+)
+
+// ServerInfo is a standard envelope block describing the server and call
+// that produced a response. Only the fields chosen in AddServerInfo are set.
+type ServerInfo struct {
+	Version   string `json:"version,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Tool      string `json:"tool,omitempty"`
+	Group     string `json:"group,omitempty"`
+}
+
+// ServerInfoField names a ServerInfo field for AddServerInfo.
+type ServerInfoField string
+
+// Fields of the ServerInfo envelope.
+const (
+	ServerInfoVersion   ServerInfoField = "version"
+	ServerInfoTimestamp ServerInfoField = "timestamp"
+	ServerInfoTool      ServerInfoField = "tool"
+	ServerInfoGroup     ServerInfoField = "group"
+)
+
+// AddServerInfo enriches every execution response with a server_info
+// block holding the given fields, or all of them when none are given:
+// the capabilities version, the UTC completion time, and the tool name and
+// group. It runs as an after hook, so it composes with other hooks and
+// leaves the result, error and meta of the response untouched.
+func (p *SimpleProvider) AddServerInfo(fields ...ServerInfoField) {
+	if len(fields) == 0 {
+		fields = []ServerInfoField{ServerInfoVersion, ServerInfoTimestamp, ServerInfoTool, ServerInfoGroup}
+	}
+
+	p.AddAfterHook(func(ctx context.Context, toolName string, resp *ExecuteResponse) {
+		info := &ServerInfo{}
+		for _, field := range fields {
+			switch field {
+			case ServerInfoVersion:
+				info.Version = p.capabilities.Version
+			case ServerInfoTimestamp:
+				info.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+			case ServerInfoTool:
+				info.Tool = toolName
+			case ServerInfoGroup:
+				info.Group = GroupIDFromContext(ctx)
+			}
+		}
+		resp.ServerInfo = info
+	})
+}
@@ -0,0 +1,82 @@
+package a2t
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SignatureSuffix is appended to the capabilities path to form the path of
+// its detached signature.
+const SignatureSuffix = ".sig"
+
+// ErrInvalidSignature is returned by VerifyCapabilities when the signature
+// doesn't match the document.
+var ErrInvalidSignature = errors.New("a2t: invalid capabilities signature")
+
+// WithSigningKey signs the capabilities document with an Ed25519 key. The
+// base64 detached signature is served next to the document, at its path
+// plus SignatureSuffix.
+func WithSigningKey(key ed25519.PrivateKey) ServerOption {
+	return func(s *Server) {
+		s.signingKey = key
+	}
+}
+
+// VerifyCapabilities checks a capabilities document against the detached
+// signature served with it. The signature covers the exact bytes of the
+// document as served, so doc must be the response body, unmodified.
+func VerifyCapabilities(doc, sig []byte, pubkey ed25519.PublicKey) error {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("a2t: decoding signature: %w", err)
+	}
+	if !ed25519.Verify(pubkey, doc, raw) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// capabilitiesSignature serves the base64 signature of the capabilities
+// document, computed over the body a GET of the document gets.
+func (s *Server) capabilitiesSignature(w http.ResponseWriter, r *http.Request) {
+	doc := &capturedResponse{header: make(http.Header), status: http.StatusOK}
+	get := r.Clone(r.Context())
+	get.Method = http.MethodGet
+	s.capabilitiesHandler.ServeHTTP(doc, get)
+
+	if doc.status != http.StatusOK {
+		writeError(w, r, http.StatusInternalServerError, &ErrorDetail{
+			Code:    "internal_error",
+			Message: "Failed to encode capabilities",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(ed25519.Sign(s.signingKey, doc.body.Bytes()))))
+}
+
+// capturedResponse records a response, headers included, without sending
+// it.
+type capturedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (c *capturedResponse) Header() http.Header {
+	return c.header
+}
+
+func (c *capturedResponse) WriteHeader(status int) {
+	c.status = status
+}
+
+func (c *capturedResponse) Write(p []byte) (int, error) {
+	return c.body.Write(p)
+}
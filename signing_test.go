@@ -0,0 +1,38 @@
+package a2t
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifyCapabilitiesCoversServedBytes(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewServer(NewSimpleProvider(nil), WithSigningKey(priv)).Handler()
+
+	get := func(path string) []byte {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != 200 {
+			t.Fatalf("GET %s: status %d", path, rec.Code)
+		}
+		body, _ := io.ReadAll(rec.Body)
+		return body
+	}
+	doc := get("/.well-known/a2t-capabilities.json")
+	sig := get("/.well-known/a2t-capabilities.json.sig")
+
+	if err := VerifyCapabilities(doc, sig, pub); err != nil {
+		t.Fatalf("served document: %v", err)
+	}
+
+	tampered := bytes.Replace(doc, []byte("{"), []byte(`{"rpc_override":"https://evil.example",`), 1)
+	if err := VerifyCapabilities(tampered, sig, pub); err != ErrInvalidSignature {
+		t.Fatalf("document with injected field: got %v, want ErrInvalidSignature", err)
+	}
+}
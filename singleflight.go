@@ -0,0 +1,91 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// WithSingleFlight makes identical concurrent calls share one execution:
+// while a call is running, calls with the same params wait for it and get
+// its result or error instead of running the executor again. Nothing is
+// kept once it finishes, so errors aren't reused by later calls.
+//
+// Calls are matched on the tool and its params, after defaults and group
+// params are applied, so use it only for tools whose result depends on
+// those alone, not on the caller. Hooks and executor middleware still run
+// per call. Output streamed by writer tools is never shared.
+func (t *Tool) WithSingleFlight() *Tool {
+	t.singleFlight = true
+	return t
+}
+
+// flightGroup tracks the executions in flight for single-flight tools.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is one shared execution.
+type flight struct {
+	done   chan struct{}
+	result interface{}
+	err    error
+}
+
+// singleFlight wraps the executor of a single-flight tool so identical
+// concurrent calls share one run. Other executors are returned unchanged.
+func (p *SimpleProvider) singleFlight(entry registeredTool) ToolExecutor {
+	key, executor := entry.key, entry.executor
+	if !entry.tool.singleFlight || entry.writer != nil {
+		return executor
+	}
+
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		canonical, err := json.Marshal(params)
+		if err != nil {
+			return executor(ctx, params)
+		}
+		// Map keys are marshaled sorted, so equal params give equal keys
+		flightKey := key.groupID + "/" + key.name + "\x00" + string(canonical)
+		return p.flights.do(ctx, flightKey, func() (interface{}, error) {
+			return executor(ctx, params)
+		})
+	}
+}
+
+// do runs fn, unless a call with the same key is in flight, in which case
+// it waits for that call's outcome or for ctx to be done.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	if f, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.result, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*flight)
+	}
+	// Waiters get an internal_error if fn panics
+	f := &flight{done: make(chan struct{}), err: &ErrorDetail{
+		Code:    "internal_error",
+		Message: "Shared execution panicked",
+	}}
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	// Release waiters even if fn panics
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+
+	f.result, f.err = fn()
+	return f.result, f.err
+}
@@ -0,0 +1,111 @@
+package a2t
+
+import "fmt"
+
+// CatalogSnapshot is a copy of a provider's tools and groups as data.
+// Executors are not captured; a restored tool runs the executor currently
+// registered under its group and name.
+type CatalogSnapshot struct {
+	Tools  []Tool  `json:"tools"`
+	Groups []Group `json:"groups,omitempty"`
+
+	// Aliases maps the qualified name of each tool with param aliases
+	// ("group/name" for grouped tools) to its aliases and their canonical
+	// params, so they survive a JSON round trip.
+	Aliases map[string]map[string]string `json:"aliases,omitempty"`
+}
+
+// Snapshot captures the registered tools and their param aliases.
+func (p *SimpleProvider) Snapshot() *CatalogSnapshot {
+	snapshot := &CatalogSnapshot{Tools: p.AllTools()}
+	for i := range snapshot.Tools {
+		tool := &snapshot.Tools[i]
+		if len(tool.aliases) == 0 {
+			continue
+		}
+		if snapshot.Aliases == nil {
+			snapshot.Aliases = make(map[string]map[string]string)
+		}
+		snapshot.Aliases[qualifiedName(tool)] = copyStrings(tool.aliases)
+	}
+	return snapshot
+}
+
+// Restore replaces the registered tools with the snapshot's. Each tool is
+// bound to the executor registered under its group and name; if any has
+// none, Restore returns an error and leaves the catalog unchanged.
+// Executions already running are unaffected.
+func (p *SimpleProvider) Restore(snapshot *CatalogSnapshot) error {
+	p.catalogMu.Lock()
+	defer p.catalogMu.Unlock()
+
+	tools, executors, writers, err := p.rebind(snapshot)
+	if err != nil {
+		return err
+	}
+	p.tools, p.executors, p.writers = tools, executors, writers
+	return nil
+}
+
+// rebind builds the tool and executor maps for a snapshot from the current
+// executor bindings. The caller must hold p.catalogMu.
+func (p *SimpleProvider) rebind(snapshot *CatalogSnapshot) (map[toolKey]*Tool, map[toolKey]ToolExecutor, map[toolKey]WriterExecutor, error) {
+	tools := make(map[toolKey]*Tool, len(snapshot.Tools))
+	executors := make(map[toolKey]ToolExecutor, len(snapshot.Tools))
+	writers := make(map[toolKey]WriterExecutor)
+
+	for i := range snapshot.Tools {
+		tool := copyTool(&snapshot.Tools[i])
+		key := toolKey{groupID: tool.GroupID, name: tool.Name}
+		if aliases, ok := snapshot.Aliases[qualifiedName(&tool)]; ok {
+			tool.aliases = copyStrings(aliases)
+		}
+
+		executor, ok := p.executors[key]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("restoring catalog: no executor registered for tool %s", qualifiedName(&tool))
+		}
+		tools[key] = &tool
+		executors[key] = executor
+		if writer, ok := p.writers[key]; ok {
+			writers[key] = writer
+		}
+	}
+	return tools, executors, writers, nil
+}
+
+// Snapshot captures the registered tools and groups.
+func (p *GroupProviderImpl) Snapshot() *CatalogSnapshot {
+	snapshot := p.SimpleProvider.Snapshot()
+	snapshot.Groups = p.AllGroups()
+	for i := range snapshot.Groups {
+		snapshot.Groups[i] = copyGroup(&snapshot.Groups[i])
+	}
+	return snapshot
+}
+
+// Restore replaces the registered tools and groups with the snapshot's,
+// under the catalog and group locks. Tools are bound to executors as on
+// SimpleProvider.
+func (p *GroupProviderImpl) Restore(snapshot *CatalogSnapshot) error {
+	groups := make(map[string]*Group, len(snapshot.Groups))
+	for i := range snapshot.Groups {
+		group := copyGroup(&snapshot.Groups[i])
+		groups[group.ID] = &group
+	}
+
+	p.catalogMu.Lock()
+	defer p.catalogMu.Unlock()
+
+	tools, executors, writers, err := p.rebind(snapshot)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.tools, p.executors, p.writers = tools, executors, writers
+	p.groups = groups
+	return nil
+}
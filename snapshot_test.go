@@ -0,0 +1,75 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+func TestRestoreConcurrentWithExecution(t *testing.T) {
+	p := NewGroupProvider(NewCapabilities())
+	p.RegisterGroup(NewGroup("math", "Math", "Math tools"))
+	echo := func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return params, nil
+	}
+	p.RegisterTool(NewTool("echo", "Echo params"), echo)
+	p.RegisterTool(NewTool("add", "Add numbers").WithGroup("math"), echo)
+	snapshot := p.Snapshot()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := p.ExecuteTool(ctx, "echo", map[string]interface{}{}); err != nil {
+					t.Error(err)
+				}
+				if _, err := p.ListTools(ctx, "", "", 0, 0); err != nil {
+					t.Error(err)
+				}
+				if _, err := p.GetGroup(ctx, "math"); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	for j := 0; j < 100; j++ {
+		if err := p.Restore(snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
+
+func TestSnapshotAliasesSurviveJSON(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	p.RegisterTool(NewTool("weather", "Get weather").
+		WithProperty("location", "string", "City", true).
+		WithParamAlias("location", "city"),
+		func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return params["location"], nil
+		})
+
+	data, err := json.Marshal(p.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snapshot CatalogSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Restore(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := p.ExecuteTool(context.Background(), "weather", map[string]interface{}{"city": "SF"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != nil || resp.Result != "SF" {
+		t.Fatalf("got result %v, error %v; want SF", resp.Result, resp.Error)
+	}
+}
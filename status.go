@@ -0,0 +1,81 @@
+package a2t
+
+import (
+	"context"
+	"time"
+
+	"github.com/swaggest/usecase"
+)
+
+// ServerStatus is a lightweight load hint: how busy the server is and how
+// much it serves, without a metrics endpoint.
+type ServerStatus struct {
+	InFlight      int64     `json:"in_flight" description:"Tool executions currently running"`
+	Tools         int       `json:"tools" description:"Tools registered and visible to the caller"`
+	Groups        int       `json:"groups,omitempty" description:"Groups registered and visible to the caller"`
+	StartedAt     time.Time `json:"started_at"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+}
+
+// WithStatus serves a ServerStatus at the endpoint, "/status" by default.
+// It is off unless enabled, as it reveals load to any caller.
+func (c *Capabilities) WithStatus(endpoint string) *Capabilities {
+	if endpoint == "" {
+		endpoint = "/status"
+	}
+	c.Endpoints.Status = endpoint
+	return c
+}
+
+// statusUsecase reports the server's status.
+func (s *Server) statusUsecase() usecase.Interactor {
+	u := usecase.NewInteractor(func(ctx context.Context, input struct{}, output *ServerStatus) error {
+		tools, err := s.countTools(ctx)
+		if err != nil {
+			return err
+		}
+		*output = ServerStatus{
+			InFlight:      s.inFlight.Load(),
+			Tools:         tools,
+			StartedAt:     s.startedAt,
+			UptimeSeconds: int64(time.Since(s.startedAt).Seconds()),
+		}
+
+		if groupProvider, ok := s.provider.(GroupProvider); ok && s.provider.GetCapabilities().Features.Groups {
+			groups, err := groupProvider.ListGroups(ctx, "", "", 0, 0)
+			if err != nil {
+				return err
+			}
+			output.Groups = groups.Total
+		}
+		return nil
+	})
+
+	u.SetName("getStatus")
+	u.SetTags("Capabilities")
+	u.SetTitle("Get Status")
+	u.SetDescription("Returns a lightweight load hint: executions in flight, tools and groups registered, and uptime")
+
+	return u
+}
+
+// countTools counts the tools visible to the caller, using CountTools when
+// the provider has it and a listing otherwise.
+func (s *Server) countTools(ctx context.Context) (int, error) {
+	if counter, ok := s.provider.(interface{ CountTools(ctx context.Context) int }); ok {
+		return counter.CountTools(ctx), nil
+	}
+	tools, err := s.provider.ListTools(ctx, "", "", 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	return tools.Total, nil
+}
+
+// tracked runs an execution, counting it as in flight for the status
+// endpoint.
+func (s *Server) tracked(run func() (*ExecuteResponse, error)) (*ExecuteResponse, error) {
+	s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	return run()
+}
@@ -0,0 +1,33 @@
+package a2t
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestStatusCountsVisibleTools(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities().WithStatus(""))
+	p.RegisterTool(NewTool("add", "Add numbers"), echoExecutor)
+	p.RegisterTool(NewTool("subtract", "Subtract numbers"), echoExecutor)
+	p.RegisterTool(NewTool("divide", "Divide numbers").WithAvailable(func(ctx context.Context) bool {
+		return false
+	}), echoExecutor)
+	h := NewServer(p).Handler()
+
+	var status ServerStatus
+	if err := json.Unmarshal(serve(h, "GET", "/status", "").Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Tools != 2 {
+		t.Errorf("status reports %d tools, want 2", status.Tools)
+	}
+
+	resp, err := p.ListTools(context.Background(), "", "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.CountTools(context.Background()); got != resp.Total {
+		t.Errorf("CountTools = %d, listing total = %d", got, resp.Total)
+	}
+}
@@ -0,0 +1,342 @@
+package a2t
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DefaultStreamContentType is the content type of writer tools that don't
+// declare one.
+const DefaultStreamContentType = "application/octet-stream"
+
+// StreamErrorTrailer is the HTTP trailer carrying the ErrorDetail, as JSON,
+// of a streamed response that failed after output was sent.
+const StreamErrorTrailer = "X-A2T-Error"
+
+// WriterExecutor is a tool executor that writes its output as raw bytes
+// instead of returning a result.
+type WriterExecutor func(ctx context.Context, params map[string]interface{}, w io.Writer) error
+
+// ArrayEmitter sends one element of a streamed array result. It returns an
+// error when the element can't be encoded or written, after which the
+// executor should stop.
+type ArrayEmitter func(element interface{}) error
+
+// ArrayExecutor is a tool executor that produces an array result one
+// element at a time.
+type ArrayExecutor func(ctx context.Context, params map[string]interface{}, emit ArrayEmitter) error
+
+// ToolStreamer is an optional interface for providers with writer tools.
+// Tools with a ContentType are streamed through it rather than executed.
+type ToolStreamer interface {
+	// StreamTool runs the named tool, writing its output to w.
+	StreamTool(ctx context.Context, toolName string, params map[string]interface{}, w io.Writer) error
+}
+
+// WithContentType sets the media type of a writer tool's output.
+func (t *Tool) WithContentType(contentType string) *Tool {
+	t.ContentType = contentType
+	return t
+}
+
+// RegisterWriterTool registers a tool whose executor writes raw bytes. Over
+// HTTP the output is sent as the response body with the tool's ContentType,
+// application/octet-stream by default, and flushed as it is written. Other
+// transports buffer the output and return it as the result: a string for
+// text content types and base64 otherwise.
+func (p *SimpleProvider) RegisterWriterTool(tool *Tool, fn WriterExecutor) {
+	if tool.ContentType == "" {
+		tool.ContentType = DefaultStreamContentType
+	}
+	p.mustRegister(tool, bufferedExecutor(tool.ContentType, fn), fn)
+}
+
+// RegisterArrayStreamTool registers a tool whose result is a JSON array the
+// executor produces element by element. Over HTTP the array is sent as the
+// application/json response body as elements are emitted, so large results
+// aren't buffered, yet the body is still a plain JSON array. An error after
+// elements were sent leaves the array unterminated, so clients can't
+// mistake it for a complete result, and is reported in the
+// StreamErrorTrailer trailer. Other transports collect the elements and
+// return them as the result.
+func (p *SimpleProvider) RegisterArrayStreamTool(tool *Tool, fn ArrayExecutor) {
+	tool.ContentType = "application/json"
+	executor := func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		elements := []interface{}{}
+		err := fn(ctx, params, func(element interface{}) error {
+			elements = append(elements, element)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return elements, nil
+	}
+	p.mustRegister(tool, executor, arrayWriter(fn))
+}
+
+// arrayWriter adapts an array executor to write a JSON array. The opening
+// bracket is held back until the first element, so errors raised before
+// then are answered as usual.
+func arrayWriter(fn ArrayExecutor) WriterExecutor {
+	return func(ctx context.Context, params map[string]interface{}, w io.Writer) error {
+		opened := false
+		err := fn(ctx, params, func(element interface{}) error {
+			data, err := json.Marshal(element)
+			if err != nil {
+				return err
+			}
+			sep := ","
+			if !opened {
+				sep, opened = "[", true
+			}
+			_, err = w.Write(append([]byte(sep), data...))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if !opened {
+			_, err = io.WriteString(w, "[]")
+			return err
+		}
+		_, err = io.WriteString(w, "]")
+		return err
+	}
+}
+
+// StreamTool runs a writer tool with the same availability, param checks and
+// hooks as ExecuteTool. Meta responses set by after hooks are ignored.
+func (p *SimpleProvider) StreamTool(ctx context.Context, toolName string, params map[string]interface{}, w io.Writer) error {
+	entry, params, errDetail := p.prepare(ctx, toolName, params)
+	if errDetail != nil {
+		return errDetail
+	}
+	fn := entry.writer
+	if fn == nil {
+		return &ErrorDetail{
+			Code:    "execution_error",
+			Message: "Tool does not stream its output: " + toolName,
+		}
+	}
+
+	executor := func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return nil, fn(ctx, params, w)
+	}
+	resp := p.execute(ctx, entry.tool, executor, params)
+	for _, hook := range p.afterHooks {
+		hook(ctx, toolName, resp)
+	}
+
+	if resp.Error != nil {
+		return resp.Error
+	}
+	return nil
+}
+
+// bufferedExecutor adapts a writer executor for transports that need the
+// whole result.
+func bufferedExecutor(contentType string, fn WriterExecutor) ToolExecutor {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var buf bytes.Buffer
+		if err := fn(ctx, params, &buf); err != nil {
+			return nil, err
+		}
+		if isTextContentType(contentType) {
+			return buf.String(), nil
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// isTextContentType reports whether a media type holds text.
+func isTextContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// streamTools serves execute requests for tools with a ContentType by
+// streaming their output, passing other requests to next.
+func (s *Server) streamTools(next http.Handler) http.Handler {
+	streamer, ok := s.provider.(ToolStreamer)
+	getter, isGetter := s.provider.(ToolGetter)
+	if !ok || !isGetter {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		groupID, name := chi.URLParam(r, "id"), chi.URLParam(r, "name")
+		tool, err := getter.GetTool(WithGroupID(r.Context(), groupID), name)
+		if err != nil || tool.ContentType == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		s.stream(w, r, streamer, groupID, tool)
+	})
+}
+
+// stream runs a writer tool and sends its output as the response body.
+// Errors raised before the first byte is written are answered like any
+// other execution error. Later ones end the response early, after a final
+// "error" event for text/event-stream tools, and are reported in the
+// StreamErrorTrailer trailer; data already sent is kept.
+func (s *Server) stream(w http.ResponseWriter, r *http.Request, streamer ToolStreamer, groupID string, tool *Tool) {
+	ctx, cleanup, err := s.requestContext(r.Context(), r)
+	defer cleanup()
+	if err == nil {
+		if groupID != "" {
+			ctx = WithGroupID(ctx, groupID)
+		}
+		err = s.streamTo(ctx, w, r, streamer, groupID, tool)
+	}
+	if err != nil {
+		status, body := makeErrResp(ctx, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+// streamTo decodes params and streams the tool's output to w. It returns an
+// error only when nothing has been written yet.
+func (s *Server) streamTo(ctx context.Context, w http.ResponseWriter, r *http.Request, streamer ToolStreamer, groupID string, tool *Tool) error {
+	if err := matchETag(tool, r.Header.Get("If-Match")); err != nil {
+		return err
+	}
+
+	params, values, err := decodeParams(r)
+	if err != nil {
+		return err
+	}
+	params, err = s.resolveParams(ctx, tool.Name, params, values)
+	if err != nil {
+		return err
+	}
+
+	out := &flushWriter{w: w, contentType: tool.ContentType}
+	resp, err := s.pipeline(ctx, groupID, tool.Name, params, func() (*ExecuteResponse, error) {
+		if err := streamer.StreamTool(ctx, tool.Name, params, out); err != nil {
+			var detail *ErrorDetail
+			if !errors.As(err, &detail) {
+				return nil, err
+			}
+			return &ExecuteResponse{Error: detail}, nil
+		}
+		return &ExecuteResponse{}, nil
+	})
+
+	switch {
+	case out.started:
+		if err == nil && resp.Error != nil {
+			err = resp.Error
+		}
+		if err != nil {
+			s.logger().ErrorContext(ctx, "streaming tool output", "tool", tool.Name, "error", err)
+			if isEventStream(tool.ContentType) {
+				out.writeErrorEvent(ctx, err)
+			}
+			out.setErrorTrailer(ctx, err)
+		}
+		return nil
+	case err != nil:
+		return err
+	case resp.Error != nil && resp.Error.Status != 0:
+		return resp.Error
+	case resp.Error != nil:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&ExecuteResponse{Error: withRequestID(ctx, resp.Error)})
+		return nil
+	}
+
+	out.start()
+	return nil
+}
+
+// logger returns the provider's logger when it exposes one, and
+// slog.Default() otherwise.
+func (s *Server) logger() *slog.Logger {
+	if p, ok := s.provider.(interface{ Logger() *slog.Logger }); ok && p.Logger() != nil {
+		return p.Logger()
+	}
+	return slog.Default()
+}
+
+// flushWriter writes a streamed response body, sending headers on the first
+// write and flushing after every write.
+type flushWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	started     bool
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	f.start()
+	n, err := f.w.Write(p)
+	if err == nil {
+		_ = http.NewResponseController(f.w).Flush()
+	}
+	return n, err
+}
+
+// writeErrorEvent ends an event stream with an "error" event carrying the
+// ErrorDetail, so clients can tell a failure from a clean completion.
+func (f *flushWriter) writeErrorEvent(ctx context.Context, err error) {
+	data, err := json.Marshal(streamErrorDetail(ctx, err))
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(f, "event: error\ndata: %s\n\n", data)
+}
+
+// setErrorTrailer reports a mid-stream failure in the StreamErrorTrailer
+// trailer, for clients that read trailers.
+func (f *flushWriter) setErrorTrailer(ctx context.Context, err error) {
+	data, err := json.Marshal(streamErrorDetail(ctx, err))
+	if err != nil {
+		return
+	}
+	f.w.Header().Set(StreamErrorTrailer, string(data))
+}
+
+// streamErrorDetail returns the ErrorDetail reported for a mid-stream
+// error.
+func streamErrorDetail(ctx context.Context, err error) *ErrorDetail {
+	var detail *ErrorDetail
+	if !errors.As(err, &detail) {
+		detail = &ErrorDetail{Code: "internal_error", Message: err.Error()}
+	}
+	return withRequestID(ctx, detail)
+}
+
+// isEventStream reports whether a content type is text/event-stream.
+func isEventStream(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "text/event-stream"
+}
+
+// start sends the response headers once.
+func (f *flushWriter) start() {
+	if f.started {
+		return
+	}
+	f.started = true
+	f.w.Header().Set("Content-Type", f.contentType)
+	f.w.Header().Set("X-Content-Type-Options", "nosniff")
+	f.w.Header().Set("Trailer", StreamErrorTrailer)
+	f.w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,90 @@
+package a2t
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// countingReplayStore counts the records saved to a MemoryReplayStore.
+type countingReplayStore struct {
+	*MemoryReplayStore
+	mu    sync.Mutex
+	saved []ReplayRecord
+}
+
+func (c *countingReplayStore) Save(ctx context.Context, record ReplayRecord) error {
+	c.mu.Lock()
+	c.saved = append(c.saved, record)
+	c.mu.Unlock()
+	return c.MemoryReplayStore.Save(ctx, record)
+}
+
+// recordingSink keeps the audit entries it receives.
+type recordingSink struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+func (r *recordingSink) Record(ctx context.Context, entry AuditEntry) {
+	r.mu.Lock()
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+}
+
+func TestStreamedExecutionIsAuditedAndRecorded(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	p.RegisterWriterTool(NewTool("report", "Write a report"), func(ctx context.Context, params map[string]interface{}, w io.Writer) error {
+		_, err := io.WriteString(w, "all good\n")
+		return err
+	})
+	store := &countingReplayStore{MemoryReplayStore: NewMemoryReplayStore(0)}
+	sink := &recordingSink{}
+	h := NewServer(p, WithReplayRecorder(store), WithAuditSink(sink)).Handler()
+
+	rec := serve(h, "POST", "/tools/report", `{"topic": "sales"}`)
+	if rec.Code != http.StatusOK || rec.Body.String() != "all good\n" {
+		t.Fatalf("got %d %q", rec.Code, rec.Body)
+	}
+
+	if len(store.saved) != 1 || store.saved[0].Tool != "report" || store.saved[0].Params["topic"] != "sales" {
+		t.Errorf("replay records = %+v, want one for report", store.saved)
+	}
+	if len(sink.entries) != 1 || sink.entries[0].Tool != "report" || sink.entries[0].Status != "ok" {
+		t.Errorf("audit entries = %+v, want one ok entry for report", sink.entries)
+	}
+}
+
+func TestStreamErrorsUnwrapAndUseProviderLogger(t *testing.T) {
+	p := NewSimpleProvider(NewCapabilities())
+	var logs bytes.Buffer
+	p.SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+	p.RegisterWriterTool(NewTool("denied", "Refuse before writing"), func(ctx context.Context, params map[string]interface{}, w io.Writer) error {
+		return fmt.Errorf("checking access: %w", &ErrorDetail{Code: "forbidden", Message: "No access", Status: http.StatusForbidden})
+	})
+	p.RegisterWriterTool(NewTool("partial", "Fail after writing"), func(ctx context.Context, params map[string]interface{}, w io.Writer) error {
+		_, _ = io.WriteString(w, "half")
+		return errors.New("disk gone")
+	})
+	h := NewServer(p).Handler()
+
+	rec := serve(h, "POST", "/tools/denied", `{}`)
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), `"forbidden"`) {
+		t.Errorf("wrapped error: got %d %s, want 403 forbidden", rec.Code, rec.Body)
+	}
+
+	rec = serve(h, "POST", "/tools/partial", `{}`)
+	if rec.Body.String() != "half" {
+		t.Errorf("partial body = %q, want %q", rec.Body, "half")
+	}
+	if !strings.Contains(logs.String(), "streaming tool output") || !strings.Contains(logs.String(), "disk gone") {
+		t.Errorf("provider logger got %q, want the stream error", logs.String())
+	}
+}
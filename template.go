@@ -0,0 +1,74 @@
+package a2t
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ResultWholeValue is the ResultTemplate path selecting the whole result.
+const ResultWholeValue = "$"
+
+// WithResultTemplate reshapes the tool's result for clients: each key of
+// the template becomes a field of the result, holding the value at a dotted
+// path into the executor's result, such as "user.name" or "items.0", or the
+// whole result for "$". Fields whose path is absent are omitted. Templates
+// are checked when the tool is registered; see ResultTemplate.
+func (t *Tool) WithResultTemplate(template map[string]string) *Tool {
+	t.ResultTemplate = template
+	return t
+}
+
+// validateResultTemplate checks that every field of the template has a
+// name and a well-formed path.
+func validateResultTemplate(template map[string]string) error {
+	for field, path := range template {
+		if field == "" {
+			return fmt.Errorf("result template: field name must not be empty")
+		}
+		if path == ResultWholeValue {
+			continue
+		}
+		for _, part := range strings.Split(path, ".") {
+			if part == "" {
+				return fmt.Errorf("result template: field %s: invalid path %q", field, path)
+			}
+		}
+	}
+	return nil
+}
+
+// applyResultTemplate reshapes a successful result with the tool's
+// template. A result that doesn't fit the template is logged and replaced
+// with an internal_error.
+func (p *SimpleProvider) applyResultTemplate(ctx context.Context, tool *Tool, resp *ExecuteResponse) *ExecuteResponse {
+	if len(tool.ResultTemplate) == 0 || resp.Error != nil || resp.Content != nil {
+		return resp
+	}
+
+	shaped := make(map[string]interface{}, len(tool.ResultTemplate))
+	for field, path := range tool.ResultTemplate {
+		var parts []string
+		if path != ResultWholeValue {
+			parts = strings.Split(path, ".")
+		}
+		value, ok, err := lookupPath(resp.Result, parts)
+		if err != nil {
+			p.logger.ErrorContext(ctx, "tool result does not fit result template",
+				"tool", tool.Name,
+				"field", field,
+				"error", err,
+				"request_id", RequestIDFromContext(ctx),
+			)
+			return &ExecuteResponse{Error: &ErrorDetail{
+				Code:    "internal_error",
+				Message: "Internal error while executing " + tool.Name,
+			}}
+		}
+		if ok {
+			shaped[field] = value
+		}
+	}
+	resp.Result = shaped
+	return resp
+}
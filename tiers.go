@@ -0,0 +1,84 @@
+package a2t
+
+import (
+	"context"
+	"sync"
+)
+
+// CapabilitiesFunc returns the capabilities for the caller in ctx, such as
+// those of the tier in the caller's claims, or nil for the provider's own.
+type CapabilitiesFunc func(ctx context.Context) *Capabilities
+
+// WithTieredCapabilities serves per-caller capabilities, so callers on
+// different tiers see and get different limits and feature flags. The
+// provider's capabilities stay in effect for callers fn returns nil for,
+// such as anonymous ones.
+//
+// Routing is fixed when the server is created, so a tier's endpoints and
+// the groups and group-only features come from the provider. Limits, and
+// the validate_params and validate_output features, are honored per tier.
+// Return the same *Capabilities for a tier on every call: its concurrency
+// limits are counted per Limits value.
+func WithTieredCapabilities(fn CapabilitiesFunc) ServerOption {
+	return func(s *Server) {
+		s.tiers = fn
+	}
+}
+
+type capabilitiesKey struct{}
+
+// capabilitiesFor returns the capabilities in effect for the caller in ctx.
+func (s *Server) capabilitiesFor(ctx context.Context) *Capabilities {
+	if s.tiers != nil {
+		if caps := s.tiers(ctx); caps != nil {
+			return caps
+		}
+	}
+	return s.provider.GetCapabilities()
+}
+
+// withCapabilities records the caller's capabilities for the provider.
+func (s *Server) withCapabilities(ctx context.Context) context.Context {
+	if s.tiers == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, capabilitiesKey{}, s.capabilitiesFor(ctx))
+}
+
+// capabilitiesFor returns the capabilities recorded for the call in ctx,
+// the provider's own unless the server serves tiers.
+func (p *SimpleProvider) capabilitiesFor(ctx context.Context) *Capabilities {
+	if caps, ok := ctx.Value(capabilitiesKey{}).(*Capabilities); ok {
+		return caps
+	}
+	return p.capabilities
+}
+
+// tierLimiters caches the execution limiters of tier limits.
+type tierLimiters struct {
+	mu       sync.Mutex
+	limiters map[*LimitsConfig]*executionLimiter
+}
+
+// tierLimiter returns the execution limiter for the caller's tier: the
+// server's own for the provider's limits, one built from the tier's limits
+// otherwise.
+func (s *Server) tierLimiter(ctx context.Context) *executionLimiter {
+	limits := s.capabilitiesFor(ctx).Limits
+	if limits == s.provider.GetCapabilities().Limits {
+		return s.limiter
+	}
+
+	s.tierLimiters.mu.Lock()
+	defer s.tierLimiters.mu.Unlock()
+
+	limiter, ok := s.tierLimiters.limiters[limits]
+	if !ok {
+		if s.tierLimiters.limiters == nil {
+			s.tierLimiters.limiters = make(map[*LimitsConfig]*executionLimiter)
+		}
+		limiter = newExecutionLimiter(limits)
+		s.tierLimiters.limiters[limits] = limiter
+	}
+	return limiter
+}
@@ -0,0 +1,42 @@
+package a2t
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Timing headers set on execution responses when WithTimingHeaders is on.
+const (
+	DurationHeader    = "X-A2T-Duration-Ms"
+	ResultBytesHeader = "X-A2T-Result-Bytes"
+)
+
+// WithTimingHeaders sets X-A2T-Duration-Ms and X-A2T-Result-Bytes on tool
+// execution responses, so clients can log per-call latency and payload size
+// without a metrics endpoint. Streamed responses don't get them.
+func WithTimingHeaders() ServerOption {
+	return func(s *Server) {
+		s.timingHeaders = true
+	}
+}
+
+// timeExecutions buffers an execution response to report how long it took
+// and how large its body is. It is a no-op unless timing headers are on.
+func (s *Server) timeExecutions(next http.Handler) http.Handler {
+	if !s.timingHeaders {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		buf := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+
+		h := w.Header()
+		h.Set(DurationHeader, strconv.FormatInt(time.Since(start).Milliseconds(), 10))
+		h.Set(ResultBytesHeader, strconv.Itoa(buf.body.Len()))
+		w.WriteHeader(buf.status)
+		_, _ = w.Write(buf.body.Bytes())
+	})
+}
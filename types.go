@@ -1,17 +1,65 @@
 package a2t
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // Tool represents a callable function that an AI agent can invoke.
 // The schema matches standard LLM tool calling formats (OpenAI, Anthropic, etc.)
 type Tool struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	InputSchema map[string]interface{} `json:"input_schema"`
-	GroupID     string                 `json:"group_id,omitempty"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	InputSchema  map[string]interface{} `json:"input_schema"`
+	OutputSchema map[string]interface{} `json:"output_schema,omitempty"`
+	GroupID      string                 `json:"group_id,omitempty"`
+	Scopes       []string               `json:"scopes,omitempty"`
+	Cost         *CostHint              `json:"cost,omitempty"`
+	ContentType  string                 `json:"content_type,omitempty" description:"Media type of the raw response body of a streaming tool"`
+	ReadOnly     bool                   `json:"read_only,omitempty" description:"Advisory: the tool has no side effects and is safe to call speculatively"`
+
+	// ETag fingerprints the tool's name, group and schemas. It is set on
+	// registration; send it in If-Match when executing to fail with 412
+	// instead of running a tool that has changed.
+	ETag string `json:"etag,omitempty" description:"Fingerprint of the tool's name, group and schemas, for If-Match on execution"`
+
+	// Highlights locates search matches. Only set in listings that asked
+	// for highlighting.
+	Highlights []Highlight `json:"highlights,omitempty"`
+
+	// ResultTemplate maps client-facing result fields to paths into the
+	// executor's result. See WithResultTemplate.
+	ResultTemplate map[string]string `json:"-"`
+
+	errorMapper   ErrorMapper
+	available     func(ctx context.Context) bool
+	descriptions  map[string]string
+	validators    []fieldValidator
+	preprocessors []fieldPreprocessor
+	aliases       map[string]string
+	errs          []error
+	singleFlight  bool
+
+	// CreatedAt and UpdatedAt default to registration time and are used for sorting.
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// Relative cost levels for CostHint.
+const (
+	CostLow    = "low"
+	CostMedium = "medium"
+	CostHigh   = "high"
+)
+
+// CostHint is advisory metadata about how expensive a tool is to call, for
+// cost-aware planners choosing between tools.
+type CostHint struct {
+	Level    string  `json:"level,omitempty" enum:"low,medium,high"`
+	Estimate float64 `json:"estimate,omitempty"`
+	Unit     string  `json:"unit,omitempty" description:"Unit of the estimate, such as USD or tokens"`
 }
 
 // Group organizes tools hierarchically.
@@ -21,6 +69,31 @@ type Group struct {
 	Description string `json:"description"`
 	ParentID    string `json:"parent_id,omitempty"`
 	ToolCount   int    `json:"tool_count"`
+
+	// Icon, Color and Order are display hints for clients rendering a group
+	// browser. Icon is an emoji or image URL, Color a CSS color. Groups with
+	// an Order come first when listed with sort=order.
+	Icon  string `json:"icon,omitempty"`
+	Color string `json:"color,omitempty"`
+	Order int    `json:"order,omitempty"`
+
+	// Limits overrides the server's concurrency limits for tools executed
+	// within the group. Unset fields fall back to the server's limits.
+	Limits *LimitsConfig `json:"limits,omitempty"`
+
+	// DefaultParams are passed to the group's tools for params the caller
+	// omits.
+	DefaultParams map[string]interface{} `json:"-"`
+
+	injector   ParamInjector
+	callerWins bool
+
+	// Translations keyed by lowercase language tag
+	names        map[string]string
+	descriptions map[string]string
+
+	// CreatedAt defaults to registration time and is used for sorting.
+	CreatedAt time.Time `json:"-"`
 }
 
 // Capabilities declares what features a server supports.
@@ -29,6 +102,9 @@ type Capabilities struct {
 	Features  FeatureSet           `json:"features"`
 	Endpoints EndpointConfig       `json:"endpoints"`
 	Limits    *LimitsConfig        `json:"limits,omitempty"`
+
+	// Experimental lists enabled preview features. The server fills it in.
+	Experimental []ExperimentalFeature `json:"experimental,omitempty"`
 }
 
 // FeatureSet defines which optional features are enabled.
@@ -36,32 +112,141 @@ type FeatureSet struct {
 	Groups       bool `json:"groups"`
 	Search       bool `json:"search"`
 	DynamicTools bool `json:"dynamic_tools"`
+	GroupOnly    bool `json:"group_only,omitempty"`
+
+	// ValidateParams reports whether execution parameters are checked
+	// against tools' input schemas, including formats and patterns.
+	ValidateParams bool `json:"validate_params,omitempty"`
+
+	// ValidateOutput reports whether results are checked against tools'
+	// output schemas before being returned.
+	ValidateOutput bool `json:"validate_output,omitempty"`
+
+	// Meta reports whether executions may carry meta responses, and
+	// MetaTypes lists the meta types that may appear.
+	Meta      bool     `json:"meta"`
+	MetaTypes []string `json:"meta_types,omitempty"`
 }
 
 // EndpointConfig defines the URL paths for each endpoint.
 type EndpointConfig struct {
-	Tools  string `json:"tools"`
-	Groups string `json:"groups,omitempty"`
+	Tools     string `json:"tools,omitempty"`
+	Groups    string `json:"groups,omitempty"`
+	RPC       string `json:"rpc,omitempty"`
+	Status    string `json:"status,omitempty"`
+	WellKnown string `json:"well_known,omitempty"`
+}
+
+// DefaultWellKnownPath is the standard discovery path for the capabilities document.
+const DefaultWellKnownPath = "/.well-known/a2t-capabilities.json"
+
+// WellKnownPath returns the capabilities document path, falling back to
+// DefaultWellKnownPath when no override is configured.
+func (e EndpointConfig) WellKnownPath() string {
+	if e.WellKnown == "" {
+		return DefaultWellKnownPath
+	}
+	return e.WellKnown
 }
 
+// Page size caps enforced when LimitsConfig leaves them unset.
+const (
+	DefaultMaxToolsPerRequest  = 100
+	DefaultMaxGroupsPerRequest = 50
+)
+
+// DefaultMaxBatchCalls caps the calls of a batch when LimitsConfig leaves
+// MaxBatchCalls unset.
+const DefaultMaxBatchCalls = 50
+
+// Page sizes used when a listing omits limit and LimitsConfig leaves the
+// defaults unset.
+const (
+	DefaultToolsPageSize  = 100
+	DefaultGroupsPageSize = 50
+)
+
 // LimitsConfig defines server-side limits.
 type LimitsConfig struct {
 	MaxToolsPerRequest   int `json:"max_tools_per_request,omitempty"`
 	MaxGroupsPerRequest  int `json:"max_groups_per_request,omitempty"`
 	MaxSearchResults     int `json:"max_search_results,omitempty"`
+
+	// DefaultToolsLimit and DefaultGroupsLimit are the page sizes of listings
+	// that omit limit. They never exceed the matching Max cap.
+	DefaultToolsLimit  int `json:"default_tools_limit,omitempty"`
+	DefaultGroupsLimit int `json:"default_groups_limit,omitempty"`
+
+	// MaxConcurrentExecutions caps in-flight executions across all tools,
+	// and MaxConcurrentPerTool caps them for each tool. Zero means unlimited.
+	MaxConcurrentExecutions int `json:"max_concurrent_executions,omitempty"`
+	MaxConcurrentPerTool    int `json:"max_concurrent_per_tool,omitempty"`
+
+	// MaxBatchCalls caps the calls of a batch execution, and the requests
+	// of a JSON-RPC batch.
+	MaxBatchCalls int `json:"max_batch_calls,omitempty"`
+
+	// QueueTimeout is how long a call waits for a free execution slot before
+	// failing as overloaded. Zero rejects immediately.
+	QueueTimeout time.Duration `json:"-"`
+
+	// MaxQueueDepth caps how many calls wait for a slot at once; calls past
+	// it are rejected right away. Zero means no cap.
+	MaxQueueDepth int `json:"max_queue_depth,omitempty"`
+
+	// OverloadBehavior advertises whether calls without a free slot are
+	// queued (OverloadQueue) or rejected (OverloadReject). It is derived from
+	// QueueTimeout when unset; setting OverloadReject disables queueing.
+	OverloadBehavior string `json:"overload_behavior,omitempty" enum:"queue,reject"`
+
+	// RetryAfter is the delay advised in the Retry-After header of
+	// overloaded responses, DefaultRetryAfter when zero.
+	RetryAfter time.Duration `json:"-"`
+
+	// ResultBufferTTL is how long the remaining pages of a PagedResult stay
+	// readable, and MaxBufferedResults caps how many paged results are held
+	// at once, evicting the oldest.
+	ResultBufferTTL    time.Duration `json:"-"`
+	MaxBufferedResults int           `json:"max_buffered_results,omitempty"`
 }
 
 // ExecuteResponse is the response from tool execution.
+// Tools respond with either a plain Result or a list of Content blocks.
 type ExecuteResponse struct {
-	Result interface{}   `json:"result"`
-	Error  *ErrorDetail  `json:"error,omitempty"`
-	Meta   *MetaResponse `json:"meta,omitempty"`
+	Result  interface{}    `json:"result"`
+	Content []ContentBlock `json:"content,omitempty"`
+	Error   *ErrorDetail   `json:"error,omitempty"`
+	Meta    *MetaResponse  `json:"meta,omitempty"`
+
+	// Warnings are non-fatal advisories about a successful result.
+	Warnings []Warning `json:"warnings,omitempty"`
+
+	// ServerInfo is the envelope added by SimpleProvider.AddServerInfo.
+	ServerInfo *ServerInfo `json:"server_info,omitempty"`
 }
 
 // ErrorDetail provides structured error information.
 type ErrorDetail struct {
-	Code    string `json:"code"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+	Debug     string `json:"debug,omitempty"`
+
+	// Details locates the error in specific params, for clients that
+	// highlight the fields to fix.
+	Details []FieldError `json:"details,omitempty"`
+
+	// Status is the HTTP status to answer with. When unset, execution
+	// errors are returned with 200 in the ExecuteResponse envelope.
+	Status int `json:"-"`
+}
+
+// FieldError is a problem with one param. Field is a dotted path into the
+// params, as in "address.zip".
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
 }
 
 // Error implements the error interface.
@@ -69,6 +254,12 @@ func (e *ErrorDetail) Error() string {
 	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
+// ErrorResponse is the response body for errors raised by the server itself,
+// outside of tool execution.
+type ErrorResponse struct {
+	Error *ErrorDetail `json:"error"`
+}
+
 // MetaResponse contains metadata that clients can intercept.
 type MetaResponse struct {
 	Type interface{} `json:"type"`
@@ -88,19 +279,32 @@ type MetaGroupRefresh struct {
 }
 
 // ToolsResponse is the response for listing tools.
+// HasMore reports whether another page follows; NextOffset is its offset.
 type ToolsResponse struct {
-	Tools  []Tool         `json:"tools"`
-	Total  int            `json:"total,omitempty"`
-	Offset int            `json:"offset,omitempty"`
-	Limit  int            `json:"limit,omitempty"`
+	Tools      []Tool `json:"tools"`
+	Total      int    `json:"total,omitempty"`
+	Offset     int    `json:"offset,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	NextOffset int    `json:"next_offset,omitempty"`
 }
 
 // GroupsResponse is the response for listing groups.
+// HasMore reports whether another page follows; NextOffset is its offset.
 type GroupsResponse struct {
-	Groups []Group `json:"groups"`
-	Total  int     `json:"total,omitempty"`
-	Offset int     `json:"offset,omitempty"`
-	Limit  int     `json:"limit,omitempty"`
+	Groups     []Group `json:"groups"`
+	Total      int     `json:"total,omitempty"`
+	Offset     int     `json:"offset,omitempty"`
+	Limit      int     `json:"limit,omitempty"`
+	HasMore    bool    `json:"has_more"`
+	NextOffset int     `json:"next_offset,omitempty"`
+}
+
+// GroupResponse is a single group. Ancestors holds its parent chain, root
+// first, when requested.
+type GroupResponse struct {
+	Group
+	Ancestors []Group `json:"ancestors,omitempty"`
 }
 
 // NewCapabilities creates a basic capabilities configuration.
@@ -128,6 +332,28 @@ func (c *Capabilities) WithGroups(endpoint string) *Capabilities {
 	return c
 }
 
+// WithGroupOnly hides the flat tools endpoints so tools are only listed and
+// executed through their groups. It enables groups if they aren't already.
+func (c *Capabilities) WithGroupOnly() *Capabilities {
+	if !c.Features.Groups {
+		c.WithGroups("")
+	}
+	c.Features.GroupOnly = true
+	c.Endpoints.Tools = ""
+	return c
+}
+
+// WithMeta advertises that executions may carry meta responses of the given types.
+func (c *Capabilities) WithMeta(types ...string) *Capabilities {
+	c.Features.Meta = true
+	for _, t := range types {
+		if !containsString(c.Features.MetaTypes, t) {
+			c.Features.MetaTypes = append(c.Features.MetaTypes, t)
+		}
+	}
+	return c
+}
+
 // WithSearch enables search feature.
 func (c *Capabilities) WithSearch() *Capabilities {
 	c.Features.Search = true
@@ -140,6 +366,13 @@ func (c *Capabilities) WithDynamicTools() *Capabilities {
 	return c
 }
 
+// WithWellKnownPath overrides the path the capabilities document is served from.
+// Clients discovering the server by convention still expect DefaultWellKnownPath.
+func (c *Capabilities) WithWellKnownPath(path string) *Capabilities {
+	c.Endpoints.WellKnown = path
+	return c
+}
+
 // WithLimits sets server limits.
 func (c *Capabilities) WithLimits(limits *LimitsConfig) *Capabilities {
 	c.Limits = limits
@@ -164,9 +397,18 @@ func NewTool(name, description string) *Tool {
 	}
 }
 
-// WithProperty adds a property to the tool's input schema.
+// WithProperty adds a property to the tool's input schema. propType must be
+// a JSON Schema type, and a property can be defined only once: later
+// definitions are dropped. Both mistakes are reported by Errors.
 func (t *Tool) WithProperty(name, propType, description string, required bool) *Tool {
 	props := t.InputSchema["properties"].(map[string]interface{})
+	if _, ok := props[name]; ok {
+		t.errs = append(t.errs, fmt.Errorf("property %q is defined more than once", name))
+		return t
+	}
+	if !validSchemaTypes[propType] {
+		t.errs = append(t.errs, fmt.Errorf("property %q: type %q is not a valid JSON Schema type", name, propType))
+	}
 	props[name] = map[string]interface{}{
 		"type":        propType,
 		"description": description,
@@ -180,12 +422,237 @@ func (t *Tool) WithProperty(name, propType, description string, required bool) *
 	return t
 }
 
+// WithFormat sets the JSON Schema format of a property, such as "email",
+// "uri", "date", "date-time", "uuid", "ipv4" or "ipv6". The property must
+// already be defined with WithProperty.
+func (t *Tool) WithFormat(name, format string) *Tool {
+	if prop := t.property(name); prop != nil {
+		prop["format"] = format
+	}
+	return t
+}
+
+// WithPattern sets a regular expression a string property must match. The
+// property must already be defined with WithProperty.
+func (t *Tool) WithPattern(name, pattern string) *Tool {
+	if prop := t.property(name); prop != nil {
+		prop["pattern"] = pattern
+	}
+	return t
+}
+
+// WithDefault sets the value used for a property the caller omits. Defaults
+// also fill the example request shown in the OpenAPI docs. The property must
+// already be defined with WithProperty.
+func (t *Tool) WithDefault(name string, value interface{}) *Tool {
+	if prop := t.property(name); prop != nil {
+		prop["default"] = value
+	}
+	return t
+}
+
+// WithExample sets the example request shown in the OpenAPI docs, replacing
+// the one synthesized from defaults.
+func (t *Tool) WithExample(params map[string]interface{}) *Tool {
+	t.InputSchema["example"] = params
+	return t
+}
+
+// WithRequiredIf makes requiredFields required when conditionField equals
+// conditionValue, expressed as a JSON Schema if/then clause in allOf.
+func (t *Tool) WithRequiredIf(conditionField string, conditionValue interface{}, requiredFields ...string) *Tool {
+	clause := map[string]interface{}{
+		"if": map[string]interface{}{
+			"properties": map[string]interface{}{
+				conditionField: map[string]interface{}{"const": conditionValue},
+			},
+			"required": []string{conditionField},
+		},
+		"then": map[string]interface{}{
+			"required": append([]string(nil), requiredFields...),
+		},
+	}
+
+	allOf, _ := t.InputSchema["allOf"].([]interface{})
+	t.InputSchema["allOf"] = append(allOf, clause)
+	return t
+}
+
+// Errors returns the mistakes made building the tool, such as a property
+// defined twice or with an unknown type. Registering a tool with errors
+// panics, and RegisterToolChecked and Validate return the first one.
+func (t *Tool) Errors() []error {
+	return t.errs
+}
+
+// property returns the schema of a top-level input property, or nil.
+func (t *Tool) property(name string) map[string]interface{} {
+	props, _ := t.InputSchema["properties"].(map[string]interface{})
+	prop, _ := props[name].(map[string]interface{})
+	return prop
+}
+
+// Clone returns a deep copy of the tool. Builder methods modify the tool
+// they are called on, so clone a shared base before customizing it:
+//
+//	base := NewTool("search", "Search records").WithProperty("query", "string", "Search text", true)
+//	byDate := base.Clone().WithProperty("since", "string", "Earliest date", false)
+func (t *Tool) Clone() *Tool {
+	c := copyTool(t)
+	return &c
+}
+
+// copyTool returns a copy of the tool whose InputSchema shares no maps or
+// slices with the original, so callers can mutate it freely.
+func copyTool(t *Tool) Tool {
+	c := *t
+	c.InputSchema = deepCopyMap(t.InputSchema)
+	c.OutputSchema = deepCopyMap(t.OutputSchema)
+	if t.Scopes != nil {
+		c.Scopes = append([]string(nil), t.Scopes...)
+	}
+	if t.Cost != nil {
+		cost := *t.Cost
+		c.Cost = &cost
+	}
+	if t.validators != nil {
+		c.validators = append([]fieldValidator(nil), t.validators...)
+	}
+	if t.preprocessors != nil {
+		c.preprocessors = append([]fieldPreprocessor(nil), t.preprocessors...)
+	}
+	c.descriptions = copyStrings(t.descriptions)
+	c.ResultTemplate = copyStrings(t.ResultTemplate)
+	c.aliases = copyStrings(t.aliases)
+	if t.errs != nil {
+		c.errs = append([]error(nil), t.errs...)
+	}
+	return c
+}
+
+// copyGroup returns a copy of g that shares no mutable state with it.
+func copyGroup(g *Group) Group {
+	c := *g
+	if g.Limits != nil {
+		limits := *g.Limits
+		c.Limits = &limits
+	}
+	c.names = copyStrings(g.names)
+	c.descriptions = copyStrings(g.descriptions)
+	c.DefaultParams = deepCopyMap(g.DefaultParams)
+	return c
+}
+
+// copyStrings copies a string map, keeping nil as nil.
+func copyStrings(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// deepCopyMap recursively copies a JSON-like map.
+func deepCopyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = deepCopyValue(v)
+	}
+	return out
+}
+
+// deepCopyValue recursively copies maps and slices found in JSON-like values.
+func deepCopyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return deepCopyMap(val)
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = deepCopyValue(item)
+		}
+		return out
+	case []string:
+		out := make([]string, len(val))
+		copy(out, val)
+		return out
+	default:
+		return v
+	}
+}
+
 // WithGroup sets the group ID for the tool.
 func (t *Tool) WithGroup(groupID string) *Tool {
 	t.GroupID = groupID
 	return t
 }
 
+// WithScopes sets the scopes a caller must hold to see and execute the tool.
+func (t *Tool) WithScopes(scopes ...string) *Tool {
+	t.Scopes = append(t.Scopes, scopes...)
+	return t
+}
+
+// WithCost sets the tool's relative cost level: CostLow, CostMedium or CostHigh.
+func (t *Tool) WithCost(level string) *Tool {
+	if t.Cost == nil {
+		t.Cost = &CostHint{}
+	}
+	t.Cost.Level = level
+	return t
+}
+
+// WithCostEstimate sets an estimated cost per call in the given unit.
+func (t *Tool) WithCostEstimate(estimate float64, unit string) *Tool {
+	if t.Cost == nil {
+		t.Cost = &CostHint{}
+	}
+	t.Cost.Estimate = estimate
+	t.Cost.Unit = unit
+	return t
+}
+
+// WithReadOnly marks the tool as free of side effects. It is advisory: it
+// lets planners call the tool speculatively but is not enforced.
+func (t *Tool) WithReadOnly() *Tool {
+	t.ReadOnly = true
+	return t
+}
+
+// WithAdditionalProperties sets whether the tool accepts params its input
+// schema doesn't define. By default extras are passed through; when
+// disallowed, calls with unknown params fail with invalid_params.
+func (t *Tool) WithAdditionalProperties(allowed bool) *Tool {
+	t.InputSchema["additionalProperties"] = allowed
+	return t
+}
+
+// WithErrorMapper sets a mapper for errors returned by this tool's executor.
+// It is consulted before the provider's mapper.
+func (t *Tool) WithErrorMapper(mapper ErrorMapper) *Tool {
+	t.errorMapper = mapper
+	return t
+}
+
+// WithAvailable sets a predicate evaluated per request that decides whether
+// the tool is offered. Unavailable tools are left out of listings and fail to
+// execute with tool_unavailable. Tools are always available by default.
+func (t *Tool) WithAvailable(fn func(ctx context.Context) bool) *Tool {
+	t.available = fn
+	return t
+}
+
+// isAvailable reports whether the tool is offered for the request in ctx.
+func (t *Tool) isAvailable(ctx context.Context) bool {
+	return t.available == nil || t.available(ctx)
+}
+
 // NewGroup creates a new group.
 func NewGroup(id, name, description string) *Group {
 	return &Group{
@@ -206,3 +673,56 @@ func (g *Group) WithToolCount(count int) *Group {
 	g.ToolCount = count
 	return g
 }
+
+// WithIcon sets the group's icon, an emoji or image URL.
+func (g *Group) WithIcon(icon string) *Group {
+	g.Icon = icon
+	return g
+}
+
+// WithColor sets the group's display color.
+func (g *Group) WithColor(color string) *Group {
+	g.Color = color
+	return g
+}
+
+// WithOrder sets the group's position when listed with sort=order. Lower
+// values come first; groups without an order follow alphabetically.
+func (g *Group) WithOrder(order int) *Group {
+	g.Order = order
+	return g
+}
+
+// WithLimits sets concurrency limits for tools executed within the group,
+// such as a low MaxConcurrentExecutions for a fragile backend.
+func (g *Group) WithLimits(limits *LimitsConfig) *Group {
+	g.Limits = limits
+	return g
+}
+
+// WithDefaultParams sets params passed to every tool of the group when the
+// caller omits them.
+func (g *Group) WithDefaultParams(params map[string]interface{}) *Group {
+	g.DefaultParams = params
+	return g
+}
+
+// WithInjectedParams sets a func computing params for every call to the
+// group's tools, such as a tenant ID from the caller's claims. Injected
+// values replace those the caller sent, unless callerWins is set, in which
+// case they only fill params the caller omits.
+func (g *Group) WithInjectedParams(fn ParamInjector, callerWins bool) *Group {
+	g.injector = fn
+	g.callerWins = callerWins
+	return g
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package a2t
+
+import "testing"
+
+func TestCloneLeavesBaseUntouched(t *testing.T) {
+	base := NewTool("search", "Search documents").
+		WithProperty("query", "string", "Search terms", true)
+
+	variant := base.Clone().
+		WithProperty("limit", "integer", "Maximum results", true).
+		WithDefault("query", "*")
+	variant.Name = "search_limited"
+
+	props := base.InputSchema["properties"].(map[string]interface{})
+	if _, ok := props["limit"]; ok {
+		t.Error("property added to the clone appeared on the base")
+	}
+	if _, ok := props["query"].(map[string]interface{})["default"]; ok {
+		t.Error("default set on the clone appeared on the base")
+	}
+	if required := base.InputSchema["required"].([]string); len(required) != 1 {
+		t.Errorf("base required = %v, want [query]", required)
+	}
+	if base.Name != "search" {
+		t.Errorf("base name = %q", base.Name)
+	}
+
+	if _, ok := variant.InputSchema["properties"].(map[string]interface{})["limit"]; !ok {
+		t.Error("clone lost its own property")
+	}
+}
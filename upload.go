@@ -0,0 +1,96 @@
+package a2t
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+// DefaultMaxUploadSize is the largest multipart request body accepted when
+// WithMaxUploadSize is not set.
+const DefaultMaxUploadSize = 32 << 20
+
+// multipartMemory is how much of a multipart body is kept in memory; larger
+// files are buffered to temporary files.
+const multipartMemory = 8 << 20
+
+// WithMaxUploadSize limits the size of multipart/form-data request bodies.
+// Larger uploads are rejected with 413.
+func WithMaxUploadSize(size int64) ServerOption {
+	return func(s *Server) {
+		s.maxUploadSize = size
+	}
+}
+
+// limitUploads caps the body size of multipart requests.
+func (s *Server) limitUploads(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType == "multipart/form-data" && r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, s.maxUploadSize)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// decodeMultipart returns the form fields of a multipart request as raw
+// values. File fields are reported by file name; their contents are made
+// available to executors through FilesFromContext.
+func decodeMultipart(r *http.Request) (url.Values, error) {
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, &ErrorDetail{
+				Code:    "payload_too_large",
+				Message: fmt.Sprintf("Upload exceeds the maximum size of %d bytes", tooLarge.Limit),
+				Status:  http.StatusRequestEntityTooLarge,
+			}
+		}
+		return nil, &ErrorDetail{
+			Code:    "invalid_params",
+			Message: "Invalid multipart body: " + err.Error(),
+		}
+	}
+
+	values := make(url.Values, len(r.MultipartForm.Value)+len(r.MultipartForm.File))
+	for name, v := range r.MultipartForm.Value {
+		values[name] = v
+	}
+	for name, headers := range r.MultipartForm.File {
+		for _, header := range headers {
+			values.Add(name, header.Filename)
+		}
+	}
+	return values, nil
+}
+
+// openFiles opens the first file uploaded for each multipart field. The
+// returned func closes them.
+func openFiles(r *http.Request) (map[string]io.Reader, func()) {
+	if r == nil || r.MultipartForm == nil || len(r.MultipartForm.File) == 0 {
+		return nil, func() {}
+	}
+
+	files := make(map[string]io.Reader, len(r.MultipartForm.File))
+	var closers []io.Closer
+	for name, headers := range r.MultipartForm.File {
+		if len(headers) == 0 {
+			continue
+		}
+		f, err := headers[0].Open()
+		if err != nil {
+			continue
+		}
+		files[name] = f
+		closers = append(closers, f)
+	}
+
+	return files, func() {
+		for _, c := range closers {
+			_ = c.Close()
+		}
+	}
+}
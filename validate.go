@@ -0,0 +1,187 @@
+package a2t
+
+import (
+	"fmt"
+	"sort"
+)
+
+// validSchemaTypes lists the JSON Schema primitive types a property may declare.
+var validSchemaTypes = map[string]bool{
+	"string":  true,
+	"number":  true,
+	"integer": true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"null":    true,
+}
+
+// ValidateCapabilities checks that the features a provider advertises are
+// ones it can serve: groups require a GroupProvider, and group-only mode
+// requires groups. NewServer panics with this error, so misconfiguration
+// surfaces at startup rather than on the first group request.
+func ValidateCapabilities(provider ToolProvider) error {
+	caps := provider.GetCapabilities()
+	if caps == nil {
+		return fmt.Errorf("provider %T returned no capabilities", provider)
+	}
+	if caps.Features.Groups {
+		if _, ok := provider.(GroupProvider); !ok {
+			return fmt.Errorf("capabilities advertise groups, but provider %T does not implement GroupProvider", provider)
+		}
+	}
+	if caps.Features.GroupOnly && !caps.Features.Groups {
+		return fmt.Errorf("capabilities advertise group_only without groups")
+	}
+	return nil
+}
+
+// Validate checks that the tool's input schema is internally consistent.
+// Every required name must be defined in properties, property types must be
+// valid JSON Schema types, and nested objects and arrays must be well-formed.
+// A ResultTemplate must have well-formed paths, and the tool must have been
+// built without Errors.
+func (t *Tool) Validate() error {
+	if t.Name == "" {
+		return invalidSchema("tool name must not be empty")
+	}
+	if len(t.errs) > 0 {
+		return invalidSchema(fmt.Sprintf("tool %q: %s", t.Name, t.errs[0].Error()))
+	}
+	if t.InputSchema == nil {
+		return invalidSchema(fmt.Sprintf("tool %q: input_schema is missing", t.Name))
+	}
+	if typ, ok := t.InputSchema["type"]; ok && typ != "object" {
+		return invalidSchema(fmt.Sprintf("tool %q: input_schema type must be \"object\", got %v", t.Name, typ))
+	}
+
+	if err := validateObjectSchema(t.InputSchema, "input_schema"); err != nil {
+		return invalidSchema(fmt.Sprintf("tool %q: %s", t.Name, err.Error()))
+	}
+	if err := validateResultTemplate(t.ResultTemplate); err != nil {
+		return invalidSchema(fmt.Sprintf("tool %q: %s", t.Name, err.Error()))
+	}
+	return nil
+}
+
+// validateObjectSchema checks the properties and required list of an object schema.
+func validateObjectSchema(schema map[string]interface{}, path string) error {
+	var props map[string]interface{}
+	if raw, ok := schema["properties"]; ok && raw != nil {
+		props, ok = raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s.properties must be an object", path)
+		}
+	}
+
+	required, err := stringList(schema["required"])
+	if err != nil {
+		return fmt.Errorf("%s.required %s", path, err.Error())
+	}
+	for _, name := range required {
+		if _, ok := props[name]; !ok {
+			return fmt.Errorf("%s.required names %q which is not defined in properties", path, name)
+		}
+	}
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := props[name].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s.properties.%s must be an object", path, name)
+		}
+		if err := validatePropertySchema(prop, path+".properties."+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePropertySchema checks a single property schema and recurses into
+// nested objects and array items.
+func validatePropertySchema(prop map[string]interface{}, path string) error {
+	types, err := schemaTypes(prop["type"])
+	if err != nil {
+		return fmt.Errorf("%s.type %s", path, err.Error())
+	}
+
+	if pattern, ok := prop["pattern"].(string); ok {
+		if _, err := compilePattern(pattern); err != nil {
+			return fmt.Errorf("%s.pattern is not a valid regular expression: %s", path, err.Error())
+		}
+	}
+
+	for _, typ := range types {
+		if !validSchemaTypes[typ] {
+			return fmt.Errorf("%s.type %q is not a valid JSON Schema type", path, typ)
+		}
+
+		switch typ {
+		case "object":
+			if err := validateObjectSchema(prop, path); err != nil {
+				return err
+			}
+		case "array":
+			raw, ok := prop["items"]
+			if !ok || raw == nil {
+				continue
+			}
+			items, ok := raw.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s.items must be an object", path)
+			}
+			if err := validatePropertySchema(items, path+".items"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// schemaTypes normalizes a schema "type" value, which may be a single string
+// or a list of strings.
+func schemaTypes(raw interface{}) ([]string, error) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	default:
+		return stringList(v)
+	}
+}
+
+// stringList converts a []string or JSON-decoded []interface{} into a []string.
+func stringList(raw interface{}) ([]string, error) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("must contain only strings, got %v", item)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("must be a list of strings")
+	}
+}
+
+// invalidSchema creates an invalid_schema error.
+func invalidSchema(message string) *ErrorDetail {
+	return &ErrorDetail{
+		Code:    "invalid_schema",
+		Message: message,
+	}
+}
@@ -0,0 +1,335 @@
+package a2t
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stringFormats checks the JSON Schema formats enforced on string values.
+// Other formats are documentation only.
+var stringFormats = map[string]func(string) bool{
+	"email": func(s string) bool {
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	},
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+	},
+	"date": func(s string) bool {
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	},
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil
+	},
+	"uuid": uuidPattern.MatchString,
+	"ipv4": func(s string) bool {
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	},
+	"ipv6": func(s string) bool {
+		return net.ParseIP(s) != nil && strings.Contains(s, ":")
+	},
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// patterns caches compiled "pattern" regular expressions.
+var patterns sync.Map
+
+// compilePattern compiles a schema pattern, reusing earlier compilations.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// validateValue checks a JSON-decoded value against a schema's type, enum,
+// const, format, pattern, minimum and maximum, properties, required, items,
+// allOf and if/then/else keywords. Errors name the offending value by its
+// path, and failed conditionals name their condition.
+func validateValue(schema map[string]interface{}, value interface{}, path string) error {
+	types, err := schemaTypes(schema["type"])
+	if err != nil {
+		return fmt.Errorf("%s: type %s", path, err)
+	}
+	if len(types) > 0 && !matchesType(types, value) {
+		return fmt.Errorf("%s: expected %v, got %s", path, types, jsonType(value))
+	}
+
+	if enum, ok := enumValues(schema); ok && !containsValue(enum, value) {
+		return fmt.Errorf("%s: %v is not one of %v", path, value, enum)
+	}
+	if c, ok := schema["const"]; ok && !equalValues(c, value) {
+		return fmt.Errorf("%s: must be %v", path, c)
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if err := checkBounds(schema, v); err != nil {
+			return fmt.Errorf("%s: %s", path, err)
+		}
+	case string:
+		if format, _ := schema["format"].(string); format != "" {
+			if check, ok := stringFormats[format]; ok && !check(v) {
+				return fmt.Errorf("%s: %q is not a valid %s", path, v, format)
+			}
+		}
+		if pattern, _ := schema["pattern"].(string); pattern != "" {
+			re, err := compilePattern(pattern)
+			if err != nil {
+				return fmt.Errorf("%s: invalid pattern %q", path, pattern)
+			}
+			if !re.MatchString(v) {
+				return fmt.Errorf("%s: %q does not match pattern %s", path, v, pattern)
+			}
+		}
+	case map[string]interface{}:
+		required, _ := stringList(schema["required"])
+		for _, name := range required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s: required property is missing", joinPath(path, name))
+			}
+		}
+
+		if err := checkAdditionalProperties(schema, v, path); err != nil {
+			return err
+		}
+
+		props, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(props))
+		for name := range props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := props[name].(map[string]interface{})
+			item, present := v[name]
+			if !ok || !present {
+				continue
+			}
+			if err := validateValue(prop, item, joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for i, item := range v {
+			if err := validateValue(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, raw := range allOf {
+			sub, _ := raw.(map[string]interface{})
+			if err := validateValue(sub, value, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if cond, ok := schema["if"].(map[string]interface{}); ok {
+		branch, _ := schema["else"].(map[string]interface{})
+		matched := validateValue(cond, value, path) == nil
+		if matched {
+			branch, _ = schema["then"].(map[string]interface{})
+		}
+		if err := validateValue(branch, value, path); err != nil {
+			if matched {
+				return fmt.Errorf("%s when %s", err, describeCondition(cond))
+			}
+			return fmt.Errorf("%s unless %s", err, describeCondition(cond))
+		}
+	}
+	return nil
+}
+
+// matchesType reports whether a JSON-decoded value is one of the schema types.
+func matchesType(types []string, value interface{}) bool {
+	actual := jsonType(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType names the JSON Schema type of a JSON-decoded value.
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// enumValues returns a schema's enum as JSON values. Enums built in code may
+// be any slice type, such as []string.
+func enumValues(schema map[string]interface{}) ([]interface{}, bool) {
+	enum, ok := schema["enum"]
+	if !ok {
+		return nil, false
+	}
+	var list []interface{}
+	if remarshal(enum, &list) != nil {
+		return nil, false
+	}
+	return list, true
+}
+
+// containsValue reports whether list holds a value equal to v, comparing
+// as equalValues does.
+func containsValue(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if equalValues(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAdditionalProperties rejects properties of value not defined in the
+// schema when it sets additionalProperties to false.
+func checkAdditionalProperties(schema, value map[string]interface{}, path string) error {
+	if allowed, ok := schema["additionalProperties"].(bool); !ok || allowed {
+		return nil
+	}
+
+	props, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(value))
+	for name := range value {
+		if _, ok := props[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%s: unknown property", joinPath(path, names[0]))
+}
+
+// joinPath appends a property name to a value path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// checkBounds checks a number against the minimum, maximum,
+// exclusiveMinimum and exclusiveMaximum keywords.
+func checkBounds(schema map[string]interface{}, v float64) error {
+	if lo, ok := toFloat(schema["minimum"]); ok && v < lo {
+		return fmt.Errorf("%v is less than the minimum %v", v, lo)
+	}
+	if hi, ok := toFloat(schema["maximum"]); ok && v > hi {
+		return fmt.Errorf("%v is greater than the maximum %v", v, hi)
+	}
+	if lo, ok := toFloat(schema["exclusiveMinimum"]); ok && v <= lo {
+		return fmt.Errorf("%v must be greater than %v", v, lo)
+	}
+	if hi, ok := toFloat(schema["exclusiveMaximum"]); ok && v >= hi {
+		return fmt.Errorf("%v must be less than %v", v, hi)
+	}
+	return nil
+}
+
+// describeCondition renders an if schema's property constraints for error
+// messages, such as "type is wire".
+func describeCondition(cond map[string]interface{}) string {
+	props, _ := cond["properties"].(map[string]interface{})
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		prop, _ := props[name].(map[string]interface{})
+		if c, ok := prop["const"]; ok {
+			parts = append(parts, fmt.Sprintf("%s is %v", name, c))
+		}
+		for _, b := range boundOperators {
+			if bound, ok := prop[b.keyword]; ok {
+				parts = append(parts, fmt.Sprintf("%s %s %v", name, b.op, bound))
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return "the condition matches"
+	}
+	return strings.Join(parts, " and ")
+}
+
+// boundOperators renders numeric bound keywords in describeCondition.
+var boundOperators = []struct{ keyword, op string }{
+	{"minimum", ">="},
+	{"exclusiveMinimum", ">"},
+	{"maximum", "<="},
+	{"exclusiveMaximum", "<"},
+}
+
+// equalValues compares JSON-like values, treating numbers of any Go type as
+// equal when they have the same value.
+func equalValues(a, b interface{}) bool {
+	var x, y interface{}
+	if remarshal(a, &x) != nil || remarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// toFloat converts a numeric schema keyword, which may be any Go number type
+// when the schema is built in code.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
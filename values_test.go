@@ -0,0 +1,34 @@
+package a2t
+
+import "testing"
+
+func TestValidateValueEnum(t *testing.T) {
+	tests := []struct {
+		name  string
+		enum  interface{}
+		value interface{}
+		ok    bool
+	}{
+		{"interface slice", []interface{}{"celsius", "fahrenheit"}, "celsius", true},
+		{"interface slice miss", []interface{}{"celsius", "fahrenheit"}, "kelvin", false},
+		{"string slice", []string{"celsius", "fahrenheit"}, "fahrenheit", true},
+		{"string slice miss", []string{"celsius", "fahrenheit"}, "kelvin", false},
+		{"int slice", []int{1, 2, 3}, 2.0, true},
+		{"int slice miss", []int{1, 2, 3}, 4.0, false},
+		{"mixed numbers", []interface{}{1, int64(2)}, 2.0, true},
+		{"objects", []interface{}{map[string]int{"x": 1}}, map[string]interface{}{"x": 1.0}, true},
+	}
+	for _, tt := range tests {
+		err := validateValue(map[string]interface{}{"enum": tt.enum}, tt.value, "unit")
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: validateValue(%v) = %v, want ok=%v", tt.name, tt.value, err, tt.ok)
+		}
+	}
+}
+
+func TestPlaceholderUsesTypedEnum(t *testing.T) {
+	prop := map[string]interface{}{"type": "string", "enum": []string{"celsius", "fahrenheit"}}
+	if got := placeholder(prop); got != "celsius" {
+		t.Errorf("placeholder = %v, want celsius", got)
+	}
+}
@@ -0,0 +1,44 @@
+package a2t
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Warning is a non-fatal advisory returned alongside a successful result,
+// such as "using cached data, live source was unavailable".
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewExecuteResponse wraps a result so an executor can attach warnings.
+// Executors return it in place of the plain result.
+func NewExecuteResponse(result interface{}) *ExecuteResponse {
+	return contentResponse(result)
+}
+
+// WithWarning adds a warning to the response.
+func (r *ExecuteResponse) WithWarning(code, message string) *ExecuteResponse {
+	r.Warnings = append(r.Warnings, Warning{Code: code, Message: message})
+	return r
+}
+
+// SetupResponseHeader summarizes the warnings of an HTTP response in a
+// Warning header, as in: 199 - "2 warnings: stale_data, partial".
+func (r *ExecuteResponse) SetupResponseHeader(h http.Header) {
+	if len(r.Warnings) == 0 {
+		return
+	}
+
+	codes := make([]string, len(r.Warnings))
+	for i, w := range r.Warnings {
+		codes[i] = w.Code
+	}
+	noun := "warnings"
+	if len(codes) == 1 {
+		noun = "warning"
+	}
+	h.Set("Warning", fmt.Sprintf("199 - %q", fmt.Sprintf("%d %s: %s", len(codes), noun, strings.Join(codes, ", "))))
+}